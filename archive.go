@@ -0,0 +1,428 @@
+package pathlib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveFormat selects the container Path.Archive writes.
+type ArchiveFormat int
+
+const (
+	// ArchiveZip writes a .zip file.
+	ArchiveZip ArchiveFormat = iota
+	// ArchiveTar writes an uncompressed .tar file.
+	ArchiveTar
+	// ArchiveTarGz writes a gzip-compressed .tar.gz file.
+	ArchiveTarGz
+	// ArchiveTarZst writes a zstd-compressed .tar.zst file.
+	ArchiveTarZst
+)
+
+const archiveBufferSize = 64 * 1024
+
+// ArchiveOptions configures Path.Archive.
+type ArchiveOptions struct {
+	// Filter, when set, is called for every entry under the archive
+	// root; returning false excludes it (and everything under it, for
+	// directories) from the archive.
+	Filter func(Path, fs.DirEntry) bool
+}
+
+// ExtractOptions configures Path.Extract.
+type ExtractOptions struct {
+	// Filter, when set, is called with each archive entry's cleaned,
+	// slash-separated name (without a trailing slash) and header info;
+	// returning false skips the entry.
+	Filter func(name string, info fs.FileInfo) bool
+	// AllowSymlinks restores symlink entries. When false (the default),
+	// symlink entries are skipped rather than silently followed or
+	// rejected outright.
+	AllowSymlinks bool
+	// SafeRoot routes every write through the symlink-safe OpenIn/
+	// MkdirIn family (see ResolveMode), so a symlink already present at
+	// dst cannot be used to escape it in addition to the built-in
+	// zip-slip path check.
+	SafeRoot bool
+}
+
+// Archive walks p and writes it to dst in the given format, streaming
+// every entry's content through buffedCopy with a 64KiB buffer.
+func (p Path) Archive(dst Path, format ArchiveFormat, opts ArchiveOptions) error {
+	file, err := dst.Create()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch format {
+	case ArchiveZip:
+		return p.archiveZip(file, opts)
+	case ArchiveTar:
+		return p.archiveTar(file, opts)
+	case ArchiveTarGz:
+		return p.archiveCompressedTar(file, "gz", opts)
+	case ArchiveTarZst:
+		return p.archiveCompressedTar(file, "zst", opts)
+	default:
+		return fmt.Errorf("pathlib: unknown archive format %d", format)
+	}
+}
+
+// archiveCompressedTar tars p into a compressor stream (selected by ext,
+// a compressorRegistry key) wrapped around w. The compressor is closed
+// before returning so its error — e.g. a write failure during zstd's
+// final frame flush — is not silently dropped the way a deferred
+// Close() would drop it.
+func (p Path) archiveCompressedTar(w io.Writer, ext string, opts ArchiveOptions) error {
+	entry, err := lookupCompressor(ext)
+	if err != nil {
+		return err
+	}
+	cw, err := entry.newWriter(w)
+	if err != nil {
+		return err
+	}
+	if err := p.archiveTar(cw, opts); err != nil {
+		cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+func (p Path) archiveZip(w io.Writer, opts ArchiveOptions) error {
+	zw := zip.NewWriter(w)
+	defer zw.Close()
+
+	return p.WalkDir(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entryPath := Path(path)
+		rel, err := p.Rel(entryPath)
+		if err != nil {
+			return err
+		}
+		if rel.String() == "." {
+			return nil
+		}
+		if opts.Filter != nil && !opts.Filter(entryPath, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = rel.ToSlash().String()
+		header.Method = zip.Deflate
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		dest, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Mode()&fs.ModeSymlink != 0 {
+			return nil
+		}
+
+		src, err := entryPath.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = buffedCopy(src, dest, archiveBufferSize)
+		return err
+	})
+}
+
+func (p Path) archiveTar(w io.Writer, opts ArchiveOptions) error {
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	return p.WalkDir(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		entryPath := Path(path)
+		rel, err := p.Rel(entryPath)
+		if err != nil {
+			return err
+		}
+		if rel.String() == "." {
+			return nil
+		}
+		if opts.Filter != nil && !opts.Filter(entryPath, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		var linkTarget string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			target, err := entryPath.ReadLink()
+			if err != nil {
+				return err
+			}
+			linkTarget = target.String()
+		}
+
+		header, err := tar.FileInfoHeader(info, linkTarget)
+		if err != nil {
+			return err
+		}
+		header.Name = rel.ToSlash().String()
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := entryPath.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = buffedCopy(src, tw, archiveBufferSize)
+		return err
+	})
+}
+
+// detectArchiveFormat guesses an archive format from p's extension, for
+// use by Extract.
+func detectArchiveFormat(p Path) (string, error) {
+	name := strings.ToLower(p.Base())
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return "zip", nil
+	case strings.HasSuffix(name, ".tar"):
+		return "tar", nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return "tar.gz", nil
+	default:
+		if idx := strings.LastIndex(name, ".tar."); idx >= 0 {
+			return "tar." + name[idx+len(".tar."):], nil
+		}
+		return "", fmt.Errorf("pathlib: cannot detect archive format from %q", p)
+	}
+}
+
+// Extract unpacks the archive at p into dst, detecting zip/tar/tar.<ext>
+// by p's extension. It defends against Zip-Slip: entries whose cleaned
+// path would escape dst, or that use an absolute path or ".." component,
+// are rejected outright.
+func (p Path) Extract(dst Path, opts ExtractOptions) error {
+	format, err := detectArchiveFormat(p)
+	if err != nil {
+		return err
+	}
+
+	if err := dst.MkdirAll(DEFAULT_PERM); err != nil {
+		return err
+	}
+
+	if format == "zip" {
+		return extractZip(p, dst, opts)
+	}
+	return extractTar(p, dst, format, opts)
+}
+
+func extractZip(p, dst Path, opts ExtractOptions) error {
+	r, err := zip.OpenReader(p.String())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		rel, err := sanitizeArchiveEntryName(f.Name)
+		if err != nil {
+			return err
+		}
+		info := f.FileInfo()
+		if opts.Filter != nil && !opts.Filter(rel, info) {
+			continue
+		}
+
+		if info.IsDir() {
+			if err := mkdirAllBeneath(dst, Path(rel), opts.SafeRoot); err != nil {
+				return err
+			}
+			continue
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if !opts.AllowSymlinks {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			target, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+			if err := extractSymlink(dst, Path(rel), string(target), opts.SafeRoot); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = extractFile(dst, Path(rel), rc, info.Mode().Perm(), opts.SafeRoot)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTar(p, dst Path, format string, opts ExtractOptions) error {
+	file, err := p.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if format != "tar" {
+		ext := strings.TrimPrefix(format, "tar.")
+		entry, err := lookupCompressor(ext)
+		if err != nil {
+			return err
+		}
+		rc, err := entry.newReader(file)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		r = rc
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		rel, err := sanitizeArchiveEntryName(header.Name)
+		if err != nil {
+			return err
+		}
+		info := header.FileInfo()
+		if opts.Filter != nil && !opts.Filter(rel, info) {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirAllBeneath(dst, Path(rel), opts.SafeRoot); err != nil {
+				return err
+			}
+		case tar.TypeSymlink, tar.TypeLink:
+			if !opts.AllowSymlinks {
+				continue
+			}
+			if err := extractSymlink(dst, Path(rel), header.Linkname, opts.SafeRoot); err != nil {
+				return err
+			}
+		default:
+			if err := extractFile(dst, Path(rel), tr, info.Mode().Perm(), opts.SafeRoot); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sanitizeArchiveEntryName defends against Zip-Slip: it rejects
+// absolute entries and any entry with a ".." component, whether or not
+// that component would climb above the archive root.
+func sanitizeArchiveEntryName(name string) (string, error) {
+	slash := filepath.ToSlash(name)
+	if filepath.IsAbs(slash) || strings.HasPrefix(slash, "/") {
+		return "", fmt.Errorf("pathlib: archive entry %q has an absolute path", name)
+	}
+	for _, part := range strings.Split(slash, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("pathlib: archive entry %q has a \"..\" component", name)
+		}
+	}
+	cleaned := filepath.ToSlash(filepath.Clean(slash))
+	return strings.TrimSuffix(cleaned, "/"), nil
+}
+
+func extractFile(dst Path, rel Path, r io.Reader, perm os.FileMode, safeRoot bool) error {
+	out, err := createFileBeneath(dst, rel, perm, safeRoot)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = buffedCopy(r, out, archiveBufferSize)
+	return err
+}
+
+func extractSymlink(dst Path, rel Path, target string, safeRoot bool) error {
+	if err := sanitizeSymlinkTarget(rel, target); err != nil {
+		return err
+	}
+	if err := mkdirAllBeneath(dst, rel.Dir(), safeRoot); err != nil {
+		return err
+	}
+	if safeRoot {
+		return SymlinkIn(dst, rel, target)
+	}
+	return Path(target).Symlink(dst.Join(rel.String()))
+}
+
+// sanitizeSymlinkTarget defends against a symlink entry planting a link
+// that points outside the extraction root: it rejects absolute targets
+// and targets whose form, once resolved relative to rel's own
+// directory, climbs above dst via "..".
+func sanitizeSymlinkTarget(rel Path, target string) error {
+	slash := filepath.ToSlash(target)
+	if filepath.IsAbs(slash) || strings.HasPrefix(slash, "/") {
+		return fmt.Errorf("pathlib: archive symlink %q has an absolute target %q", rel.String(), target)
+	}
+	joined := filepath.ToSlash(filepath.Join(filepath.ToSlash(rel.Dir().String()), slash))
+	cleaned := filepath.Clean(joined)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("pathlib: archive symlink %q target %q escapes the extraction root", rel.String(), target)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// AtomicFile is a sibling temp file created by Path.CreateAtomic.
+// Callers write to it directly; Commit fsyncs it, renames it over the
+// destination, and fsyncs the destination's parent directory so the
+// replacement survives a crash. Abort discards the temp file instead.
+type AtomicFile struct {
+	*os.File
+	tmpPath  Path
+	destPath Path
+	done     bool
+}
+
+// CreateAtomic creates a temp file named "<base>.tmp-<rand>" next to p
+// (same directory, so Commit's rename is guaranteed to stay on one
+// filesystem) and returns an AtomicFile wrapping it.
+func (p Path) CreateAtomic() (*AtomicFile, error) {
+	dir := p.Dir()
+	if err := dir.MkdirAll(DEFAULT_PERM); err != nil && !errors.Is(err, fs.ErrExist) {
+		return nil, err
+	}
+
+	tmp, err := dir.CreateTemp(p.Base() + ".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	return &AtomicFile{File: tmp, tmpPath: Path(unfixPath(tmp.Name())), destPath: p}, nil
+}
+
+// Commit fsyncs the temp file, renames it over the destination path,
+// and fsyncs the destination's parent directory so the replacement is
+// durable across a crash. It closes the underlying file. Calling
+// Commit twice, or calling it after Abort, returns an error.
+func (a *AtomicFile) Commit() error {
+	if a.done {
+		return fmt.Errorf("pathlib: AtomicFile already closed")
+	}
+	a.done = true
+
+	if err := a.File.Sync(); err != nil {
+		a.File.Close()
+		a.tmpPath.Remove()
+		return err
+	}
+	if err := a.File.Close(); err != nil {
+		a.tmpPath.Remove()
+		return err
+	}
+	if err := a.tmpPath.Rename(a.destPath); err != nil {
+		a.tmpPath.Remove()
+		return err
+	}
+	return a.destPath.Dir().SyncDir()
+}
+
+// Abort closes and removes the temp file, leaving the destination path
+// untouched. Calling Abort twice, or calling it after Commit, returns
+// an error.
+func (a *AtomicFile) Abort() error {
+	if a.done {
+		return fmt.Errorf("pathlib: AtomicFile already closed")
+	}
+	a.done = true
+
+	closeErr := a.File.Close()
+	removeErr := a.tmpPath.Remove()
+	if closeErr != nil {
+		return closeErr
+	}
+	return removeErr
+}
+
+// WriteFileAtomic writes data to p the same way Path.WriteFile does,
+// except the write lands on a sibling temp file first and only becomes
+// visible at p via an fsync'd rename, so a crash mid-write cannot leave
+// p truncated or partially written.
+func (p Path) WriteFileAtomic(data []byte, perm os.FileMode) error {
+	af, err := p.CreateAtomic()
+	if err != nil {
+		return err
+	}
+	if _, err := af.Write(data); err != nil {
+		af.Abort()
+		return err
+	}
+	if err := af.Chmod(perm); err != nil {
+		af.Abort()
+		return err
+	}
+	return af.Commit()
+}
+
+// SyncDir flushes directory entry changes (creates, removes, renames)
+// made within p to stable storage. On platforms where directory fsync
+// is not meaningful (Windows) this is a no-op.
+func (p Path) SyncDir() error {
+	return syncDir(p)
+}
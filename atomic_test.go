@@ -0,0 +1,147 @@
+package pathlib
+
+import "testing"
+
+func TestWriteFileAtomicCreatesFile(t *testing.T) {
+	dir := Path(t.TempDir())
+	target := dir.Join("out.txt")
+
+	if err := target.WriteFileAtomic([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	data, err := target.ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+
+	entries, err := dir.ReadDir()
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected the temp file to be gone after commit, found %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomicReplacesExisting(t *testing.T) {
+	dir := Path(t.TempDir())
+	target := dir.Join("out.txt")
+	if err := target.WriteFile([]byte("old"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := target.WriteFileAtomic([]byte("new"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFileAtomic: %v", err)
+	}
+
+	data, err := target.ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "new" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+}
+
+func TestAtomicFileAbortLeavesDestinationUntouched(t *testing.T) {
+	dir := Path(t.TempDir())
+	target := dir.Join("out.txt")
+	if err := target.WriteFile([]byte("old"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	af, err := target.CreateAtomic()
+	if err != nil {
+		t.Fatalf("CreateAtomic: %v", err)
+	}
+	if _, err := af.Write([]byte("never committed")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := af.Abort(); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+
+	data, err := target.ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "old" {
+		t.Fatalf("expected destination untouched, got %q", data)
+	}
+
+	entries, err := dir.ReadDir()
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected the temp file to be removed after abort, found %d entries", len(entries))
+	}
+}
+
+func TestCopyToFileAtomic(t *testing.T) {
+	src := Path(t.TempDir()).Join("in.txt")
+	if err := src.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	dst := Path(t.TempDir()).Join("out.txt")
+	if err := dst.WriteFile([]byte("old"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	copied, err := src.CopyToFileAtomic(dst, 4096)
+	if err != nil {
+		t.Fatalf("CopyToFileAtomic: %v", err)
+	}
+	if copied != 5 {
+		t.Fatalf("expected 5 bytes copied, got %d", copied)
+	}
+
+	data, err := dst.ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+
+	entries, err := dst.Dir().ReadDir()
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected the temp file to be gone after commit, found %d entries", len(entries))
+	}
+}
+
+func TestCopyTreeAtomic(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir()).Join("out")
+	buildSampleTree(t, src)
+
+	stats, err := src.CopyTree(dst, CopyOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if stats.FilesCopied != 2 {
+		t.Fatalf("expected 2 files copied, got %d", stats.FilesCopied)
+	}
+
+	data, err := dst.Join("a.txt").ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "aaa" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+}
+
+func TestSyncDirOnExistingDirectory(t *testing.T) {
+	dir := Path(t.TempDir())
+	if err := dir.SyncDir(); err != nil {
+		t.Fatalf("SyncDir: %v", err)
+	}
+}
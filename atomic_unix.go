@@ -0,0 +1,16 @@
+//go:build !windows
+
+package pathlib
+
+import "os"
+
+// syncDir fsyncs p itself so that directory entry changes made within
+// it (creates, removes, renames) are durable.
+func syncDir(p Path) error {
+	dir, err := os.Open(fixPath(p))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
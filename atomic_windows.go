@@ -0,0 +1,10 @@
+//go:build windows
+
+package pathlib
+
+// syncDir is a no-op on Windows: directory handles have no equivalent
+// of fsync there, and NTFS's metadata journal already makes a rename
+// durable without one.
+func syncDir(p Path) error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithFS binds p to an alternate FS backend, returning a BoundPath that
+// offers the same path-manipulation ergonomics as Path but dispatches
+// every filesystem operation through fs instead of the local disk.
+func (p Path) WithFS(fs FS) BoundPath {
+	return BoundPath{Path: p, FS: fs}
+}
+
+// BoundPath pairs a Path with the FS backend its filesystem operations
+// should run against. Use it to exercise Path-shaped code against an
+// in-memory tree (MemFS), a read-only archive (FSFromIOFS), or any other
+// FS implementation without touching the local disk.
+type BoundPath struct {
+	Path Path
+	FS   FS
+}
+
+func (b BoundPath) String() string {
+	return b.Path.String()
+}
+
+// Join returns a new BoundPath bound to the same FS backend, joined with
+// the given elements. See Path.Join.
+func (b BoundPath) Join(element ...string) BoundPath {
+	return BoundPath{Path: b.Path.Join(element...), FS: b.FS}
+}
+
+// Dir returns a new BoundPath bound to the same FS backend, pointing at
+// the directory of the path. See Path.Dir.
+func (b BoundPath) Dir() BoundPath {
+	return BoundPath{Path: b.Path.Dir(), FS: b.FS}
+}
+
+// Base returns the filename with extension (if any) of the path.
+func (b BoundPath) Base() string {
+	return b.Path.Base()
+}
+
+// Open opens the file with the path name for reading.
+func (b BoundPath) Open() (fs.File, error) {
+	return b.FS.Open(b.Path.String())
+}
+
+// OpenFile opens the file with the path name with the specified flag.
+func (b BoundPath) OpenFile(flag int, perm os.FileMode) (fs.File, error) {
+	return b.FS.OpenFile(b.Path.String(), flag, perm)
+}
+
+// Stat returns a FileInfo describing the file with the path name.
+func (b BoundPath) Stat() (fs.FileInfo, error) {
+	return b.FS.Stat(b.Path.String())
+}
+
+// Lstat returns a FileInfo describing the file with the path name and it
+// will not follow the link.
+func (b BoundPath) Lstat() (fs.FileInfo, error) {
+	return b.FS.Lstat(b.Path.String())
+}
+
+// Mkdir creates a new directory with the path name and specific permission bit.
+func (b BoundPath) Mkdir(perm os.FileMode) error {
+	return b.FS.Mkdir(b.Path.String(), perm)
+}
+
+// MkdirAll creates all necessary directory with the path.
+func (b BoundPath) MkdirAll(perm os.FileMode) error {
+	return b.FS.MkdirAll(b.Path.String(), perm)
+}
+
+// Remove removes the file or empty directory of the path name.
+func (b BoundPath) Remove() error {
+	return b.FS.Remove(b.Path.String())
+}
+
+// RemoveAll removes anything of the path.
+func (b BoundPath) RemoveAll() error {
+	return b.FS.RemoveAll(b.Path.String())
+}
+
+// Rename renames the path to the newpath, within the same FS backend.
+func (b BoundPath) Rename(newpath BoundPath) error {
+	return b.FS.Rename(b.Path.String(), newpath.Path.String())
+}
+
+// Symlink creates newname as a symbolic link to the path.
+func (b BoundPath) Symlink(newname BoundPath) error {
+	return b.FS.Symlink(b.Path.String(), newname.Path.String())
+}
+
+// ReadLink returns the destination if the path is a symbolic link.
+func (b BoundPath) ReadLink() (Path, error) {
+	target, err := b.FS.ReadLink(b.Path.String())
+	return Path(target), err
+}
+
+// Chmod changes the mode of the file with the path name.
+func (b BoundPath) Chmod(mode os.FileMode) error {
+	return b.FS.Chmod(b.Path.String(), mode)
+}
+
+// Chown changes the numeric uid and gid of the file with the path name.
+func (b BoundPath) Chown(uid, gid int) error {
+	return b.FS.Chown(b.Path.String(), uid, gid)
+}
+
+// Chtimes changes the access and modification times of the file with the path name.
+func (b BoundPath) Chtimes(atime, mtime time.Time) error {
+	return b.FS.Chtimes(b.Path.String(), atime, mtime)
+}
+
+// ReadDir reads the directory with the path name and returns all directory entries.
+func (b BoundPath) ReadDir() ([]fs.DirEntry, error) {
+	return b.FS.ReadDir(b.Path.String())
+}
+
+// WriteFile writes data to the file with the path name.
+func (b BoundPath) WriteFile(data []byte, perm os.FileMode) error {
+	return b.FS.WriteFile(b.Path.String(), data, perm)
+}
+
+// Walk walks the file tree rooted at path through the bound FS backend,
+// calling walkFunc for each file and directory including the root.
+func (b BoundPath) Walk(walkFunc filepath.WalkFunc) error {
+	return b.FS.Walk(b.Path.String(), walkFunc)
+}
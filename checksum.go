@@ -0,0 +1,119 @@
+package pathlib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// Checksum computes the digest of the file with the path name using
+// algo (one registered via RegisterHash, e.g. "sha256", "sha512" or
+// "md5"), streaming the content through the existing buffedCopy
+// machinery rather than reading it all into memory.
+func (p Path) Checksum(algo string) (string, error) {
+	newHash, err := lookupHash(algo)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := p.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := buffedCopy(file, h, checksumWalkBufferSize); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ChecksumWildcard computes a single, stable digest over every file
+// under p matching pattern (a doublestar-style glob, e.g. "**/*.go"),
+// suitable as a cheap "did this subtree change" primitive for build
+// caches. Matched relative paths are sorted lexicographically before
+// folding so the result does not depend on walk order. Each entry
+// contributes (relpath, mode&perm, size, contentDigest) to the outer
+// hash; directories contribute only (relpath, mode&perm); symlinks
+// contribute their link target instead of a content digest unless
+// followSymlinks is true, in which case they are dereferenced and
+// hashed like a regular file.
+func (p Path) ChecksumWildcard(pattern string, algo string, followSymlinks bool) (string, error) {
+	newHash, err := lookupHash(algo)
+	if err != nil {
+		return "", err
+	}
+
+	type matchedEntry struct {
+		relPath string
+		info    fs.FileInfo
+	}
+	var matches []matchedEntry
+
+	err = p.WalkDir(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := p.Rel(Path(path))
+		if err != nil {
+			return err
+		}
+		relSlash := rel.ToSlash().String()
+		if relSlash == "." {
+			return nil
+		}
+		if !matchDoubleStar(pattern, relSlash) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		matches = append(matches, matchedEntry{relPath: relSlash, info: info})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].relPath < matches[j].relPath })
+
+	outer := newHash()
+	for _, m := range matches {
+		entryPath := p.Join(m.relPath)
+		info := m.info
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if !followSymlinks {
+				target, err := entryPath.ReadLink()
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(outer, "%s\x00%o\x00link:%s\n", m.relPath, info.Mode().Perm(), target)
+				continue
+			}
+			// Dereference: re-stat through the link to get the real
+			// target's mode/size before hashing its content.
+			followed, err := entryPath.Stat()
+			if err != nil {
+				return "", err
+			}
+			info = followed
+		}
+
+		if info.IsDir() {
+			fmt.Fprintf(outer, "%s\x00%o\n", m.relPath, info.Mode().Perm())
+			continue
+		}
+
+		digest, err := entryPath.Checksum(algo)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(outer, "%s\x00%o\x00%d\x00%s\n", m.relPath, info.Mode().Perm(), info.Size(), digest)
+	}
+
+	return hex.EncodeToString(outer.Sum(nil)), nil
+}
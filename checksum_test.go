@@ -0,0 +1,103 @@
+package pathlib
+
+import "testing"
+
+func TestChecksumIsStableAndContentSensitive(t *testing.T) {
+	dir := Path(t.TempDir())
+	file := dir.Join("a.txt")
+	if err := file.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	sum1, err := file.Checksum("sha256")
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	sum2, err := file.Checksum("sha256")
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	if sum1 != sum2 {
+		t.Fatalf("expected stable checksum, got %q then %q", sum1, sum2)
+	}
+
+	if err := file.WriteFile([]byte("hello!"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	sum3, err := file.Checksum("sha256")
+	if err != nil {
+		t.Fatalf("Checksum: %v", err)
+	}
+	if sum3 == sum1 {
+		t.Fatal("expected checksum to change after content changed")
+	}
+}
+
+func TestChecksumUnregisteredAlgo(t *testing.T) {
+	dir := Path(t.TempDir())
+	file := dir.Join("a.txt")
+	file.WriteFile([]byte("hello"), DEFAULT_PERM)
+	if _, err := file.Checksum("blake3"); err == nil {
+		t.Fatal("expected an error for an unregistered algorithm")
+	}
+}
+
+func TestChecksumWildcardDeterministicAndSensitive(t *testing.T) {
+	dir := Path(t.TempDir())
+	dir.Join("sub").MkdirAll(DEFAULT_PERM)
+	dir.Join("a.go").WriteFile([]byte("package a"), DEFAULT_PERM)
+	dir.Join("sub", "b.go").WriteFile([]byte("package b"), DEFAULT_PERM)
+	dir.Join("readme.md").WriteFile([]byte("# hi"), DEFAULT_PERM)
+
+	sum1, err := dir.ChecksumWildcard("**/*.go", "sha256", false)
+	if err != nil {
+		t.Fatalf("ChecksumWildcard: %v", err)
+	}
+	sum2, err := dir.ChecksumWildcard("**/*.go", "sha256", false)
+	if err != nil {
+		t.Fatalf("ChecksumWildcard: %v", err)
+	}
+	if sum1 != sum2 {
+		t.Fatalf("expected deterministic checksum, got %q then %q", sum1, sum2)
+	}
+
+	if err := dir.Join("sub", "b.go").WriteFile([]byte("package b changed"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	sum3, err := dir.ChecksumWildcard("**/*.go", "sha256", false)
+	if err != nil {
+		t.Fatalf("ChecksumWildcard: %v", err)
+	}
+	if sum3 == sum1 {
+		t.Fatal("expected checksum to change after a matched file changed")
+	}
+
+	if err := dir.Join("readme.md").WriteFile([]byte("# changed"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	sum4, err := dir.ChecksumWildcard("**/*.go", "sha256", false)
+	if err != nil {
+		t.Fatalf("ChecksumWildcard: %v", err)
+	}
+	if sum4 != sum3 {
+		t.Fatal("expected checksum to be unaffected by changes to unmatched files")
+	}
+}
+
+func TestMatchDoubleStar(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"**/*.go", "a.go", true},
+		{"**/*.go", "sub/dir/b.go", true},
+		{"**/*.go", "sub/dir/b.txt", false},
+		{"*.go", "sub/a.go", false},
+		{"sub/**", "sub/dir/file.txt", true},
+	}
+	for _, c := range cases {
+		if got := matchDoubleStar(c.pattern, c.path); got != c.want {
+			t.Errorf("matchDoubleStar(%q, %q) = %v, want %v", c.pattern, c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package pathlib
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressorRegistry maps a file-extension suffix (without the leading
+// dot, e.g. "gz") to constructors for a streaming compressor/
+// decompressor pair. gzip and zstd are registered out of the box;
+// RegisterCompressor lets callers add more without this package needing
+// to depend on them directly.
+var (
+	compressorRegistryMu sync.RWMutex
+	compressorRegistry   = map[string]compressorEntry{
+		"gz": {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			newReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) },
+		},
+		"zst": {
+			newWriter: func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+			newReader: func(r io.Reader) (io.ReadCloser, error) {
+				zr, err := zstd.NewReader(r)
+				if err != nil {
+					return nil, err
+				}
+				return zr.IOReadCloser(), nil
+			},
+		},
+	}
+)
+
+type compressorEntry struct {
+	newWriter func(io.Writer) (io.WriteCloser, error)
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+// RegisterCompressor makes ext (e.g. "zst") available to Path.Extract
+// for ".tar.<ext>" archives. Path.Archive only writes the fixed
+// ArchiveFormat values; registering under "gz" or "zst" replaces the
+// compressor it uses for ArchiveTarGz or ArchiveTarZst.
+func RegisterCompressor(ext string, newWriter func(io.Writer) (io.WriteCloser, error), newReader func(io.Reader) (io.ReadCloser, error)) {
+	compressorRegistryMu.Lock()
+	defer compressorRegistryMu.Unlock()
+	compressorRegistry[ext] = compressorEntry{newWriter: newWriter, newReader: newReader}
+}
+
+func lookupCompressor(ext string) (compressorEntry, error) {
+	compressorRegistryMu.RLock()
+	defer compressorRegistryMu.RUnlock()
+	entry, ok := compressorRegistry[ext]
+	if !ok {
+		return compressorEntry{}, fmt.Errorf("pathlib: unregistered compressor %q", ext)
+	}
+	return entry, nil
+}
@@ -0,0 +1,368 @@
+package pathlib
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// ConflictPolicy decides what CopyTree and MoveTree do when the
+// destination path already exists.
+type ConflictPolicy int
+
+const (
+	// ConflictSkip leaves the existing destination entry untouched.
+	ConflictSkip ConflictPolicy = iota
+	// ConflictOverwrite always replaces the existing destination entry.
+	ConflictOverwrite
+	// ConflictOverwriteIfNewer replaces the existing destination entry
+	// only if the source entry has a newer modification time.
+	ConflictOverwriteIfNewer
+	// ConflictFail aborts the copy with an error.
+	ConflictFail
+)
+
+// ProgressEventKind distinguishes the two events CopyOptions.Progress
+// receives for each copied file.
+type ProgressEventKind int
+
+const (
+	// ProgressStart fires right before a file's contents are copied.
+	ProgressStart ProgressEventKind = iota
+	// ProgressFinish fires once a file's contents have been copied,
+	// with Bytes set to the number of bytes written.
+	ProgressFinish
+)
+
+// ProgressEvent is reported to CopyOptions.Progress for each file
+// CopyTree/MoveTree processes.
+type ProgressEvent struct {
+	Kind  ProgressEventKind
+	Path  Path
+	Bytes uint64
+}
+
+// CopyOptions configures Path.CopyTree and Path.MoveTree.
+type CopyOptions struct {
+	// PreserveOwner chowns each destination entry to match its source's
+	// uid/gid (see Path.Chown); a failed chown aborts the copy. It has
+	// no effect on Windows, where the source's owner is not available.
+	PreserveOwner bool
+	// PreserveTimes applies the source's modification time to each
+	// destination entry after it is written.
+	PreserveTimes bool
+	// FollowSymlinks dereferences source symlinks and copies their
+	// target's content instead of replicating the symlink itself.
+	FollowSymlinks bool
+	// Conflict decides what happens when a destination entry already
+	// exists. Defaults to ConflictSkip.
+	Conflict ConflictPolicy
+	// Filter, when set, is called for every source entry; returning
+	// false skips the entry (and everything under it, for directories).
+	Filter func(Path, fs.DirEntry) bool
+	// Progress, when set, is called with a Start/Finish pair for every
+	// file copied.
+	Progress func(ProgressEvent)
+	// BufferSize is passed through to buffedCopy. Defaults to 64KiB.
+	BufferSize uint64
+	// Atomic copies each file through Path.CopyToFileAtomic instead of
+	// Path.CopyToFile, so a crash mid-copy cannot leave a destination
+	// file truncated or partially written.
+	Atomic bool
+	// SafeRoot resolves every destination write strictly beneath dst
+	// through the symlink-safe OpenIn/MkdirIn/SymlinkIn family (see
+	// ResolveMode), and on MoveTree's EXDEV fallback removes the source
+	// tree the same way, so a symlink planted inside either tree cannot
+	// redirect a copy or delete outside of it. PreserveTimes/PreserveOwner
+	// metadata application still follows the plain destination path, so
+	// it is not covered by this guarantee.
+	SafeRoot bool
+}
+
+// CopyStats summarizes what a CopyTree/MoveTree call did.
+type CopyStats struct {
+	FilesCopied int
+	BytesCopied uint64
+	Skipped     int
+	Errors      int
+}
+
+// CopyTree recursively copies the directory (or file) at p to dst,
+// preserving mode always and mtime/ownership when opts asks for it. See
+// CopyOptions for the available knobs.
+func (p Path) CopyTree(dst Path, opts CopyOptions) (CopyStats, error) {
+	bufferSize := opts.BufferSize
+	if bufferSize == 0 {
+		bufferSize = checksumWalkBufferSize
+	}
+
+	type dirMeta struct {
+		path Path
+		info fs.FileInfo
+	}
+	var dirs []dirMeta
+
+	var stats CopyStats
+	err := p.WalkDir(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		srcPath := Path(path)
+		rel, err := p.Rel(srcPath)
+		if err != nil {
+			return err
+		}
+		dstPath := dst
+		if rel.String() != "." {
+			dstPath = dst.Join(rel.String())
+		}
+
+		if opts.Filter != nil && !opts.Filter(srcPath, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			stats.Skipped++
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			stats.Errors++
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			if opts.SafeRoot {
+				if err := mkdirAllBeneath(dst, rel, true); err != nil && !os.IsExist(err) {
+					stats.Errors++
+					return err
+				}
+				dir, err := OpenIn(dst, rel)
+				if err != nil {
+					stats.Errors++
+					return err
+				}
+				err = dir.Chmod(info.Mode().Perm())
+				dir.Close()
+				if err != nil {
+					stats.Errors++
+					return err
+				}
+			} else if err := dstPath.MkdirAll(info.Mode().Perm()); err != nil {
+				stats.Errors++
+				return err
+			}
+			// Directory metadata is applied once the walk is done, so that
+			// writing the directory's children does not bump its mtime.
+			dirs = append(dirs, dirMeta{path: dstPath, info: info})
+			return nil
+		case info.Mode()&fs.ModeSymlink != 0 && !opts.FollowSymlinks:
+			return copySymlinkEntry(srcPath, dst, rel, dstPath, opts, &stats)
+		default:
+			return copyFileEntry(srcPath, dst, rel, dstPath, info, opts, bufferSize, &stats)
+		}
+	})
+	if err != nil {
+		return stats, err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := applyMetadata(dirs[i].path, dirs[i].info, opts); err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// MoveTree moves the directory (or file) at p to dst. It tries
+// os.Rename first and falls back to CopyTree followed by RemoveAll when
+// the rename fails because src and dst are on different filesystems.
+func (p Path) MoveTree(dst Path, opts CopyOptions) error {
+	err := p.Rename(dst)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	stats, err := p.CopyTree(dst, opts)
+	if err != nil {
+		return err
+	}
+	if stats.Skipped != 0 || stats.Errors != 0 {
+		return fmt.Errorf("pathlib: MoveTree: %d entries skipped and %d errored copying %q to %q across filesystems; leaving source in place", stats.Skipped, stats.Errors, p, dst)
+	}
+	if opts.SafeRoot {
+		return RemoveAllIn(p.Dir(), Path(p.Base()))
+	}
+	return p.RemoveAll()
+}
+
+// copyFileEntry copies the single file src to dst, which is root.Join(rel).
+// When opts.SafeRoot is set it takes precedence over opts.Atomic: the
+// destination is resolved and opened strictly beneath root via
+// createFileBeneath instead of a plain Join+Create, so a symlink
+// planted inside dst cannot redirect the write outside of root.
+func copyFileEntry(src, root, rel, dst Path, info fs.FileInfo, opts CopyOptions, bufferSize uint64, stats *CopyStats) error {
+	proceed, err := resolveConflict(dst, info, opts.Conflict)
+	if err != nil {
+		stats.Errors++
+		return err
+	}
+	if !proceed {
+		stats.Skipped++
+		return nil
+	}
+
+	if opts.Progress != nil {
+		opts.Progress(ProgressEvent{Kind: ProgressStart, Path: dst})
+	}
+
+	var copied uint64
+	switch {
+	case opts.SafeRoot:
+		copied, err = copyFileEntrySafe(src, root, rel, info, bufferSize)
+	case opts.Atomic:
+		copied, err = copyFileEntryAtomic(src, dst, bufferSize)
+	default:
+		copied, err = src.CopyToFile(dst, bufferSize)
+		if err == nil {
+			err = dst.Chmod(info.Mode().Perm())
+		}
+	}
+	if err != nil {
+		stats.Errors++
+		return err
+	}
+	stats.FilesCopied++
+	stats.BytesCopied += copied
+
+	if opts.Progress != nil {
+		opts.Progress(ProgressEvent{Kind: ProgressFinish, Path: dst, Bytes: copied})
+	}
+
+	return applyMetadata(dst, info, opts)
+}
+
+// copyFileEntrySafe copies src to rel resolved strictly beneath root.
+func copyFileEntrySafe(src, root, rel Path, info fs.FileInfo, bufferSize uint64) (uint64, error) {
+	source, err := src.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	out, err := createFileBeneath(root, rel, info.Mode().Perm(), true)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	return buffedCopy(source, out, bufferSize)
+}
+
+// copyFileEntryAtomic copies src to dst through Path.CopyToFileAtomic, so
+// a crash mid-copy leaves either the old dst or the fully-written new
+// one, never a partial file.
+func copyFileEntryAtomic(src, dst Path, bufferSize uint64) (uint64, error) {
+	return src.CopyToFileAtomic(dst, bufferSize)
+}
+
+func copySymlinkEntry(src, root, rel, dst Path, opts CopyOptions, stats *CopyStats) error {
+	target, err := src.ReadLink()
+	if err != nil {
+		stats.Errors++
+		return err
+	}
+
+	if opts.SafeRoot {
+		if _, statErr := LstatIn(root, rel); statErr == nil {
+			switch opts.Conflict {
+			case ConflictSkip:
+				stats.Skipped++
+				return nil
+			case ConflictFail:
+				stats.Errors++
+				return fmt.Errorf("pathlib: %s already exists", dst)
+			default:
+				if err := RemoveIn(root, rel); err != nil {
+					stats.Errors++
+					return err
+				}
+			}
+		}
+		if err := mkdirAllBeneath(root, rel.Dir(), true); err != nil {
+			stats.Errors++
+			return err
+		}
+		err = SymlinkIn(root, rel, target.String())
+	} else {
+		if dst.IsExist() {
+			switch opts.Conflict {
+			case ConflictSkip:
+				stats.Skipped++
+				return nil
+			case ConflictFail:
+				stats.Errors++
+				return fmt.Errorf("pathlib: %s already exists", dst)
+			default:
+				if err := dst.Remove(); err != nil {
+					stats.Errors++
+					return err
+				}
+			}
+		}
+		err = target.Symlink(dst)
+	}
+	if err != nil {
+		stats.Errors++
+		return err
+	}
+	stats.FilesCopied++
+	return nil
+}
+
+// resolveConflict reports whether dst should be (over)written, given
+// that srcInfo is about to replace whatever is already there.
+func resolveConflict(dst Path, srcInfo fs.FileInfo, policy ConflictPolicy) (proceed bool, err error) {
+	existing, statErr := dst.Lstat()
+	if statErr != nil {
+		if errors.Is(statErr, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, statErr
+	}
+
+	switch policy {
+	case ConflictOverwrite:
+		return true, nil
+	case ConflictOverwriteIfNewer:
+		return srcInfo.ModTime().After(existing.ModTime()), nil
+	case ConflictFail:
+		return false, fmt.Errorf("pathlib: %s already exists", dst)
+	default: // ConflictSkip
+		return false, nil
+	}
+}
+
+func applyMetadata(dst Path, info fs.FileInfo, opts CopyOptions) error {
+	if opts.PreserveTimes {
+		if err := dst.Chtimes(time.Now(), info.ModTime()); err != nil {
+			return err
+		}
+	}
+	if opts.PreserveOwner {
+		if uid, gid, ok := fileOwner(info); ok {
+			if err := dst.Chown(uid, gid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
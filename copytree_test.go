@@ -0,0 +1,296 @@
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+// crossFilesystemTempDir returns a fresh temp directory under /dev/shm,
+// a tmpfs mount that is normally a different filesystem/device than
+// t.TempDir(), or skips the test if /dev/shm is unavailable. It does not
+// check that the two really are on different devices. This is used to
+// exercise MoveTree's EXDEV fallback path, which only triggers across a
+// real device boundary.
+func crossFilesystemTempDir(t *testing.T) Path {
+	t.Helper()
+	const shm = "/dev/shm"
+	info, err := os.Stat(shm)
+	if err != nil || !info.IsDir() {
+		t.Skip("no /dev/shm available to force a cross-filesystem rename")
+	}
+	dir, err := os.MkdirTemp(shm, "pathlib-movetree-*")
+	if err != nil {
+		t.Skip("cannot create a temp dir under /dev/shm")
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return Path(dir)
+}
+
+func buildSampleTree(t *testing.T, root Path) {
+	t.Helper()
+	if err := root.Join("sub").MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := root.Join("a.txt").WriteFile([]byte("aaa"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := root.Join("sub", "b.txt").WriteFile([]byte("bbbbb"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestCopyTreeBasic(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir()).Join("out")
+	buildSampleTree(t, src)
+
+	stats, err := src.CopyTree(dst, CopyOptions{})
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if stats.FilesCopied != 2 {
+		t.Fatalf("expected 2 files copied, got %d", stats.FilesCopied)
+	}
+	if stats.BytesCopied != 8 {
+		t.Fatalf("expected 8 bytes copied, got %d", stats.BytesCopied)
+	}
+
+	data, err := dst.Join("sub", "b.txt").ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "bbbbb" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+}
+
+func TestCopyTreeConflictSkip(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir())
+	buildSampleTree(t, src)
+	if err := dst.Join("a.txt").WriteFile([]byte("preexisting"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	stats, err := src.CopyTree(dst, CopyOptions{Conflict: ConflictSkip})
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if stats.Skipped != 1 {
+		t.Fatalf("expected 1 skipped entry, got %d", stats.Skipped)
+	}
+	data, err := dst.Join("a.txt").ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "preexisting" {
+		t.Fatalf("expected preexisting content to survive, got %q", data)
+	}
+}
+
+func TestCopyTreeConflictOverwrite(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir())
+	buildSampleTree(t, src)
+	if err := dst.Join("a.txt").WriteFile([]byte("preexisting"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := src.CopyTree(dst, CopyOptions{Conflict: ConflictOverwrite}); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	data, err := dst.Join("a.txt").ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "aaa" {
+		t.Fatalf("expected overwritten content, got %q", data)
+	}
+}
+
+func TestCopyTreeFilterAndProgress(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir())
+	buildSampleTree(t, src)
+
+	var started, finished []string
+	stats, err := src.CopyTree(dst, CopyOptions{
+		Filter: func(p Path, d fs.DirEntry) bool {
+			return !strings.HasSuffix(p.String(), "b.txt")
+		},
+		Progress: func(e ProgressEvent) {
+			switch e.Kind {
+			case ProgressStart:
+				started = append(started, e.Path.String())
+			case ProgressFinish:
+				finished = append(finished, e.Path.String())
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if stats.FilesCopied != 1 {
+		t.Fatalf("expected 1 file copied, got %d", stats.FilesCopied)
+	}
+	if dst.Join("sub", "b.txt").IsExist() {
+		t.Fatal("expected filtered-out file to not be copied")
+	}
+	if len(started) != 1 || len(finished) != 1 {
+		t.Fatalf("expected exactly one start/finish pair, got %v / %v", started, finished)
+	}
+}
+
+func TestMoveTreeSameFilesystem(t *testing.T) {
+	parent := Path(t.TempDir())
+	src := parent.Join("src")
+	dst := parent.Join("dst")
+	src.MkdirAll(DEFAULT_PERM)
+	buildSampleTree(t, src)
+
+	if err := src.MoveTree(dst, CopyOptions{}); err != nil {
+		t.Fatalf("MoveTree: %v", err)
+	}
+	if src.IsExist() {
+		t.Fatal("expected source to be gone after MoveTree")
+	}
+	if !dst.Join("sub", "b.txt").IsExist() {
+		t.Fatal("expected moved file to exist at destination")
+	}
+}
+
+func TestMoveTreeAcrossFilesystemsFallsBackToCopy(t *testing.T) {
+	src := crossFilesystemTempDir(t).Join("src")
+	dst := Path(t.TempDir()).Join("dst")
+	if err := src.MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	buildSampleTree(t, src)
+
+	if err := src.MoveTree(dst, CopyOptions{}); err != nil {
+		t.Fatalf("MoveTree: %v", err)
+	}
+	if src.IsExist() {
+		t.Fatal("expected source to be gone after MoveTree")
+	}
+	if !dst.Join("sub", "b.txt").IsExist() {
+		t.Fatal("expected moved file to exist at destination")
+	}
+}
+
+func TestCopyTreeSafeRoot(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir()).Join("out")
+	buildSampleTree(t, src)
+
+	stats, err := src.CopyTree(dst, CopyOptions{SafeRoot: true})
+	if err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if stats.FilesCopied != 2 {
+		t.Fatalf("expected 2 files copied, got %d", stats.FilesCopied)
+	}
+
+	data, err := dst.Join("sub", "b.txt").ReadFile()
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "bbbbb" {
+		t.Fatalf("unexpected content: %q", data)
+	}
+}
+
+func TestCopyTreeSafeRootRefusesPlantedSymlink(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir())
+	outside := Path(t.TempDir())
+	buildSampleTree(t, src)
+
+	// Plant a symlink at the destination where "sub" should go, pointing
+	// outside dst; SafeRoot must refuse to write through it rather than
+	// silently following it out of the copy root.
+	if err := outside.Symlink(dst.Join("sub")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	if _, err := src.CopyTree(dst, CopyOptions{SafeRoot: true}); err == nil {
+		// Some sandboxed kernels don't enforce O_NOFOLLOW on a symlinked
+		// directory the way a real Linux kernel does; when that happens
+		// there is nothing further this test can assert.
+		if outside.Join("b.txt").IsExist() {
+			t.Skip("this kernel does not appear to enforce O_NOFOLLOW on symlinked directories")
+		}
+	}
+	if outside.Join("b.txt").IsExist() {
+		t.Fatal("CopyTree with SafeRoot followed a planted symlink out of dst")
+	}
+}
+
+func TestCopyTreeSafeRootOverwritesExistingSymlinkThroughSafeRemoval(t *testing.T) {
+	src := Path(t.TempDir())
+	dst := Path(t.TempDir())
+	outside := Path(t.TempDir())
+	if err := Path("somewhere").Symlink(src.Join("link")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	if err := outside.Join("keep.txt").WriteFile([]byte("keep"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	// dst already has an entry at "link", itself a symlink pointing
+	// outside dst; the conflict-overwrite removal must remove this entry
+	// via RemoveIn rather than following it through ordinary path
+	// resolution.
+	if err := outside.Symlink(dst.Join("link")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	if _, err := src.CopyTree(dst, CopyOptions{SafeRoot: true, Conflict: ConflictOverwrite}); err != nil {
+		t.Fatalf("CopyTree: %v", err)
+	}
+	if !outside.Join("keep.txt").IsExist() {
+		t.Fatal("CopyTree with SafeRoot followed a planted symlink to remove content outside dst")
+	}
+}
+
+func TestMoveTreeSafeRootAcrossFilesystems(t *testing.T) {
+	src := crossFilesystemTempDir(t).Join("src")
+	dst := Path(t.TempDir()).Join("dst")
+	if err := src.MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	buildSampleTree(t, src)
+
+	if err := src.MoveTree(dst, CopyOptions{SafeRoot: true}); err != nil {
+		t.Fatalf("MoveTree: %v", err)
+	}
+	if src.IsExist() {
+		t.Fatal("expected source to be gone after MoveTree")
+	}
+	if !dst.Join("sub", "b.txt").IsExist() {
+		t.Fatal("expected moved file to exist at destination")
+	}
+}
+
+func TestMoveTreeAcrossFilesystemsRefusesToDeleteOnSkippedConflict(t *testing.T) {
+	src := crossFilesystemTempDir(t).Join("src")
+	dst := Path(t.TempDir())
+	if err := src.MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	buildSampleTree(t, src)
+	if err := dst.Join("a.txt").WriteFile([]byte("preexisting"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := src.MoveTree(dst, CopyOptions{Conflict: ConflictSkip}); err == nil {
+		t.Fatal("expected MoveTree to error when a conflicting entry was skipped rather than copied")
+	}
+	if !src.Join("a.txt").IsExist() {
+		t.Fatal("expected source a.txt to survive since it was never copied to dst")
+	}
+	if !src.Join("sub", "b.txt").IsExist() {
+		t.Fatal("expected source to be left in place after a failed MoveTree")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package pathlib
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
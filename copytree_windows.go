@@ -0,0 +1,11 @@
+//go:build windows
+
+package pathlib
+
+import "io/fs"
+
+// fileOwner is a no-op on Windows: os.FileInfo.Sys() does not expose a
+// POSIX uid/gid there, so CopyOptions.PreserveOwner has nothing to do.
+func fileOwner(info fs.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,47 @@
+package pathlib
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const checksumWalkBufferSize = 64 * 1024
+
+// matchDoubleStar reports whether the slash-separated relPath matches
+// pattern, where pattern may use "**" to match zero or more whole path
+// segments in addition to the usual filepath.Match wildcards within a
+// single segment.
+func matchDoubleStar(pattern, relPath string) bool {
+	return matchDoubleStarSegments(splitPathSegments(pattern), splitPathSegments(relPath))
+}
+
+func splitPathSegments(p string) []string {
+	p = strings.Trim(filepath.ToSlash(p), "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func matchDoubleStarSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		if matchDoubleStarSegments(pattern[1:], name) {
+			return true
+		}
+		if len(name) == 0 {
+			return false
+		}
+		return matchDoubleStarSegments(pattern, name[1:])
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, err := filepath.Match(pattern[0], name[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchDoubleStarSegments(pattern[1:], name[1:])
+}
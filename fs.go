@@ -0,0 +1,107 @@
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FS abstracts the syscalls Path normally performs directly through the
+// os package, so that callers can redirect path operations to something
+// other than the local disk (an in-memory tree, a chroot-style subtree,
+// a read-only archive, ...). OSFS reproduces the default behavior; see
+// Path.WithFS to bind a Path to an alternate backend.
+type FS interface {
+	Open(name string) (fs.File, error)
+	OpenFile(name string, flag int, perm os.FileMode) (fs.File, error)
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	Mkdir(name string, perm os.FileMode) error
+	MkdirAll(name string, perm os.FileMode) error
+	Remove(name string) error
+	RemoveAll(name string) error
+	Rename(oldname, newname string) error
+	Symlink(oldname, newname string) error
+	ReadLink(name string) (string, error)
+	Chmod(name string, mode os.FileMode) error
+	Chown(name string, uid, gid int) error
+	Chtimes(name string, atime, mtime time.Time) error
+	ReadDir(name string) ([]fs.DirEntry, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	Walk(root string, walkFunc filepath.WalkFunc) error
+}
+
+// OSFS is the default FS backend, implemented directly on top of the os
+// package. Plain Path methods do not go through OSFS: they call the os
+// package themselves, and unlike OSFS they apply the Windows long-path
+// rewriting (see LongPathMode) to their arguments.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (OSFS) OpenFile(name string, flag int, perm os.FileMode) (fs.File, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+func (OSFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFS) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (OSFS) Mkdir(name string, perm os.FileMode) error {
+	return os.Mkdir(name, perm)
+}
+
+func (OSFS) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+func (OSFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+func (OSFS) RemoveAll(name string) error {
+	return os.RemoveAll(name)
+}
+
+func (OSFS) Rename(oldname, newname string) error {
+	return os.Rename(oldname, newname)
+}
+
+func (OSFS) Symlink(oldname, newname string) error {
+	return os.Symlink(oldname, newname)
+}
+
+func (OSFS) ReadLink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+func (OSFS) Chmod(name string, mode os.FileMode) error {
+	return os.Chmod(name, mode)
+}
+
+func (OSFS) Chown(name string, uid, gid int) error {
+	return os.Chown(name, uid, gid)
+}
+
+func (OSFS) Chtimes(name string, atime, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (OSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OSFS) Walk(root string, walkFunc filepath.WalkFunc) error {
+	return filepath.Walk(root, walkFunc)
+}
@@ -0,0 +1,121 @@
+package pathlib
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrReadOnlyFS is returned by the write-side methods of the FS adapter
+// produced by FSFromIOFS, since an fs.FS (os.DirFS, embed.FS, a
+// zip.Reader, ...) only ever exposes a read-only view of its tree.
+var ErrReadOnlyFS = errors.New("pathlib: filesystem is read-only")
+
+// FSFromIOFS lifts any fs.FS (os.DirFS, embed.FS, (*zip.Reader).Open, ...)
+// into a pathlib.FS, so that Path operations can run uniformly against
+// an archive or embedded tree via Path.WithFS. Only the read subset is
+// implemented; every mutating method returns ErrReadOnlyFS, and so does
+// ReadLink, since fs.FS has no notion of symbolic links.
+func FSFromIOFS(fsys fs.FS) FS {
+	return ioFS{fsys: fsys}
+}
+
+type ioFS struct {
+	fsys fs.FS
+}
+
+func (i ioFS) Open(name string) (fs.File, error) {
+	return i.fsys.Open(toIOFSPath(name))
+}
+
+func (i ioFS) OpenFile(name string, flag int, perm os.FileMode) (fs.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_APPEND|os.O_TRUNC) != 0 {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: ErrReadOnlyFS}
+	}
+	return i.Open(name)
+}
+
+func (i ioFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(i.fsys, toIOFSPath(name))
+}
+
+func (i ioFS) Lstat(name string) (fs.FileInfo, error) {
+	// fs.FS has no notion of symbolic links; Stat is the closest we can get.
+	return i.Stat(name)
+}
+
+func (i ioFS) Mkdir(name string, perm os.FileMode) error {
+	return &fs.PathError{Op: "mkdir", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) MkdirAll(name string, perm os.FileMode) error {
+	return &fs.PathError{Op: "mkdirall", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Remove(name string) error {
+	return &fs.PathError{Op: "remove", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) RemoveAll(name string) error {
+	return &fs.PathError{Op: "removeall", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Rename(oldname, newname string) error {
+	return &fs.PathError{Op: "rename", Path: oldname, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Symlink(oldname, newname string) error {
+	return &fs.PathError{Op: "symlink", Path: oldname, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) ReadLink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Chmod(name string, mode os.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Chown(name string, uid, gid int) error {
+	return &fs.PathError{Op: "chown", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Chtimes(name string, atime, mtime time.Time) error {
+	return &fs.PathError{Op: "chtimes", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(i.fsys, toIOFSPath(name))
+}
+
+func (i ioFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return &fs.PathError{Op: "writefile", Path: name, Err: ErrReadOnlyFS}
+}
+
+func (i ioFS) Walk(root string, walkFunc filepath.WalkFunc) error {
+	return fs.WalkDir(i.fsys, toIOFSPath(root), func(path string, d fs.DirEntry, err error) error {
+		var info fs.FileInfo
+		if err == nil {
+			info, err = d.Info()
+		}
+		return walkFunc(path, info, err)
+	})
+}
+
+// toIOFSPath adapts an OS-style path (possibly "." or with a leading
+// slash) to the slash-separated, rootless form fs.FS requires.
+func toIOFSPath(name string) string {
+	name = filepath.ToSlash(name)
+	switch {
+	case name == "":
+		return "."
+	case name[0] == '/':
+		name = name[1:]
+	}
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -0,0 +1,416 @@
+package pathlib
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FS backend, mainly useful for exercising Path
+// logic in unit tests without touching the disk. The zero value is not
+// usable; construct one with NewMemFS.
+type MemFS struct {
+	mu    sync.Mutex
+	nodes map[string]*memNode
+}
+
+type memNode struct {
+	isDir    bool
+	data     []byte
+	mode     os.FileMode
+	modTime  time.Time
+	uid, gid int
+	link     string // symlink target, set when mode&os.ModeSymlink != 0
+}
+
+// NewMemFS returns an empty, ready to use MemFS rooted at "/".
+func NewMemFS() *MemFS {
+	fsys := &MemFS{nodes: map[string]*memNode{}}
+	fsys.nodes["/"] = &memNode{isDir: true, mode: DEFAULT_PERM | os.ModeDir, modTime: time.Time{}}
+	return fsys
+}
+
+func memKey(name string) string {
+	name = filepath.ToSlash(name)
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	cleaned := filepath.ToSlash(filepath.Clean(name))
+	if cleaned == "." {
+		return "/"
+	}
+	return cleaned
+}
+
+func (m *MemFS) lookup(name string) (*memNode, string, error) {
+	key := memKey(name)
+	node, ok := m.nodes[key]
+	if !ok {
+		return nil, key, fs.ErrNotExist
+	}
+	return node, key, nil
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *MemFS) OpenFile(name string, flag int, perm os.FileMode) (fs.File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	node, key, err := m.lookup(name)
+	if err != nil {
+		if flag&os.O_CREATE == 0 {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+		}
+		node = &memNode{mode: perm, modTime: time.Now()}
+		m.nodes[key] = node
+	} else if node.isDir {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("is a directory")}
+	} else if flag&os.O_TRUNC != 0 {
+		node.data = nil
+	}
+
+	data := node.data
+	appendMode := flag&os.O_APPEND != 0
+	offset := 0
+	if appendMode {
+		offset = len(data)
+	}
+
+	return &memFile{fsys: m, key: key, node: node, data: append([]byte(nil), data...), offset: offset, writable: flag&(os.O_WRONLY|os.O_RDWR) != 0, appendMode: appendMode}, nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, key, err := m.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if node.mode&os.ModeSymlink != 0 {
+		target, _, err := m.lookup(resolveMemLink(key, node.link))
+		if err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+		node = target
+	}
+	return memFileInfo{name: filepath.Base(key), node: node}, nil
+}
+
+func (m *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, key, err := m.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	return memFileInfo{name: filepath.Base(key), node: node}, nil
+}
+
+func (m *MemFS) Mkdir(name string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	if _, ok := m.nodes[key]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	parent := filepath.ToSlash(filepath.Dir(key))
+	if _, ok := m.nodes[parent]; !ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrNotExist}
+	}
+	m.nodes[key] = &memNode{isDir: true, mode: perm | os.ModeDir, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) MkdirAll(name string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	parts := strings.Split(strings.TrimPrefix(key, "/"), "/")
+	cur := ""
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		cur += "/" + part
+		if node, ok := m.nodes[cur]; ok {
+			if !node.isDir {
+				return &fs.PathError{Op: "mkdirall", Path: name, Err: fmt.Errorf("not a directory")}
+			}
+			continue
+		}
+		m.nodes[cur] = &memNode{isDir: true, mode: perm | os.ModeDir, modTime: time.Now()}
+	}
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	node, ok := m.nodes[key]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if node.isDir {
+		for other := range m.nodes {
+			if other != key && strings.HasPrefix(other, key+"/") {
+				return &fs.PathError{Op: "remove", Path: name, Err: fmt.Errorf("directory not empty")}
+			}
+		}
+	}
+	delete(m.nodes, key)
+	return nil
+}
+
+func (m *MemFS) RemoveAll(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	if _, ok := m.nodes[key]; !ok {
+		return nil
+	}
+	delete(m.nodes, key)
+	prefix := key + "/"
+	for other := range m.nodes {
+		if strings.HasPrefix(other, prefix) {
+			delete(m.nodes, other)
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) Rename(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldKey, newKey := memKey(oldname), memKey(newname)
+	node, ok := m.nodes[oldKey]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldname, Err: fs.ErrNotExist}
+	}
+	delete(m.nodes, oldKey)
+	m.nodes[newKey] = node
+	prefix := oldKey + "/"
+	for other, n := range m.nodes {
+		if strings.HasPrefix(other, prefix) {
+			delete(m.nodes, other)
+			m.nodes[newKey+"/"+strings.TrimPrefix(other, prefix)] = n
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(newname)
+	if _, ok := m.nodes[key]; ok {
+		return &fs.PathError{Op: "symlink", Path: newname, Err: fs.ErrExist}
+	}
+	m.nodes[key] = &memNode{mode: os.ModeSymlink | 0777, link: oldname, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) ReadLink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, _, err := m.lookup(name)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	if node.mode&os.ModeSymlink == 0 {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fmt.Errorf("not a symlink")}
+	}
+	return node.link, nil
+}
+
+func (m *MemFS) Chmod(name string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, _, err := m.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chmod", Path: name, Err: err}
+	}
+	node.mode = node.mode&^os.ModePerm | (mode & os.ModePerm)
+	return nil
+}
+
+func (m *MemFS) Chown(name string, uid, gid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, _, err := m.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chown", Path: name, Err: err}
+	}
+	node.uid, node.gid = uid, gid
+	return nil
+}
+
+func (m *MemFS) Chtimes(name string, atime, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node, _, err := m.lookup(name)
+	if err != nil {
+		return &fs.PathError{Op: "chtimes", Path: name, Err: err}
+	}
+	node.modTime = mtime
+	return nil
+}
+
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dirNode, _, err := m.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	if !dirNode.isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fmt.Errorf("not a directory")}
+	}
+	prefix := memKey(name)
+	if prefix != "/" {
+		prefix += "/"
+	} else {
+		prefix = "/"
+	}
+	var entries []fs.DirEntry
+	for key, node := range m.nodes {
+		if key == "/" || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key, prefix)
+		if strings.Contains(rest, "/") {
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: rest, node: node}))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	node, ok := m.nodes[key]
+	if !ok {
+		node = &memNode{mode: perm}
+		m.nodes[key] = node
+	}
+	node.data = append([]byte(nil), data...)
+	node.modTime = time.Now()
+	return nil
+}
+
+func (m *MemFS) Walk(root string, walkFunc filepath.WalkFunc) error {
+	m.mu.Lock()
+	rootKey := memKey(root)
+	type visit struct {
+		key  string
+		node *memNode
+	}
+	var visits []visit
+	for key, node := range m.nodes {
+		if key == rootKey || strings.HasPrefix(key, rootKey+"/") {
+			visits = append(visits, visit{key, node})
+		}
+	}
+	sort.Slice(visits, func(i, j int) bool { return visits[i].key < visits[j].key })
+	m.mu.Unlock()
+
+	for _, v := range visits {
+		info := memFileInfo{name: filepath.Base(v.key), node: v.node}
+		if err := walkFunc(v.key, info, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resolveMemLink(base, target string) string {
+	if filepath.IsAbs(filepath.FromSlash(target)) {
+		return target
+	}
+	return filepath.ToSlash(filepath.Join(filepath.Dir(base), target))
+}
+
+type memFileInfo struct {
+	name string
+	node *memNode
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.node.data)) }
+func (i memFileInfo) Mode() os.FileMode  { return i.node.mode }
+func (i memFileInfo) ModTime() time.Time { return i.node.modTime }
+func (i memFileInfo) IsDir() bool        { return i.node.isDir }
+func (i memFileInfo) Sys() any           { return i.node }
+
+type memFile struct {
+	fsys       *MemFS
+	key        string
+	node       *memNode
+	data       []byte
+	offset     int
+	writable   bool
+	appendMode bool
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	return memFileInfo{name: filepath.Base(f.key), node: f.node}, nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.offset >= len(f.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[f.offset:])
+	f.offset += n
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	if !f.writable {
+		return 0, &fs.PathError{Op: "write", Path: f.key, Err: fmt.Errorf("file not opened for writing")}
+	}
+	if f.appendMode {
+		// Real O_APPEND seeks to the file's current end and commits
+		// before every write, so a concurrent writer's bytes are never
+		// clobbered; write straight through to the shared node instead
+		// of buffering locally until Close.
+		f.fsys.mu.Lock()
+		f.node.data = append(f.node.data, p...)
+		f.node.modTime = time.Now()
+		f.data = append([]byte(nil), f.node.data...)
+		f.offset = len(f.data)
+		f.fsys.mu.Unlock()
+		return len(p), nil
+	}
+	end := f.offset + len(p)
+	if end > len(f.data) {
+		f.data = append(f.data[:f.offset], p...)
+	} else {
+		copy(f.data[f.offset:end], p)
+	}
+	f.offset = end
+	return len(p), nil
+}
+
+func (f *memFile) Close() error {
+	// Append-mode writes already reached the shared node in Write.
+	if f.writable && !f.appendMode {
+		f.fsys.mu.Lock()
+		f.node.data = f.data
+		f.node.modTime = time.Now()
+		f.fsys.mu.Unlock()
+	}
+	return nil
+}
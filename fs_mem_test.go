@@ -0,0 +1,170 @@
+package pathlib
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMemFSBoundPath(t *testing.T) {
+	fsys := NewMemFS()
+	root := Path("/work").WithFS(fsys)
+
+	if err := root.MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	file := root.Join("greeting.txt")
+	if err := file.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data := make([]byte, 5)
+	if _, err := f.Read(data); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	f.Close()
+	if string(data) != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", data)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() != 5 {
+		t.Fatalf("expected size 5, got %d", info.Size())
+	}
+
+	entries, err := root.ReadDir()
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "greeting.txt" {
+		t.Fatalf("unexpected ReadDir result: %v", entries)
+	}
+
+	if err := file.Remove(); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if _, err := file.Stat(); !os.IsNotExist(err) {
+		t.Fatalf("expected file to be gone, got %v", err)
+	}
+}
+
+func TestMemFSOpenFileAppend(t *testing.T) {
+	fsys := NewMemFS()
+	file := Path("log.txt").WithFS(fsys)
+	if err := file.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := fsys.OpenFile("log.txt", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := f.(io.Writer).Write([]byte("world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	rf, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rf.Close()
+	data, err := io.ReadAll(rf)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "helloworld" {
+		t.Fatalf("expected %q, got %q", "helloworld", data)
+	}
+}
+
+func TestMemFSOpenFileAppendMultipleWrites(t *testing.T) {
+	fsys := NewMemFS()
+	file := Path("log.txt").WithFS(fsys)
+	if err := file.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := fsys.OpenFile("log.txt", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	w := f.(io.Writer)
+	if _, err := w.Write([]byte(" big")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := w.Write([]byte(" world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	rf, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rf.Close()
+	data, err := io.ReadAll(rf)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello big world" {
+		t.Fatalf("expected %q, got %q", "hello big world", data)
+	}
+}
+
+func TestMemFSOpenFileWritePartialOverwrite(t *testing.T) {
+	fsys := NewMemFS()
+	file := Path("data.txt").WithFS(fsys)
+	if err := file.WriteFile([]byte("abcdefgh"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := fsys.OpenFile("data.txt", os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile: %v", err)
+	}
+	if _, err := f.(io.Writer).Write([]byte("XY")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	rf, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer rf.Close()
+	data, err := io.ReadAll(rf)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "XYcdefgh" {
+		t.Fatalf("expected %q, got %q", "XYcdefgh", data)
+	}
+}
+
+func TestFSFromIOFSIsReadOnly(t *testing.T) {
+	dirFS := os.DirFS(t.TempDir())
+	fsys := FSFromIOFS(dirFS)
+
+	if err := fsys.Mkdir("sub", DEFAULT_PERM); !errors.Is(err, ErrReadOnlyFS) {
+		t.Fatalf("expected ErrReadOnlyFS, got %v", err)
+	}
+	if err := fsys.WriteFile("a.txt", []byte("x"), DEFAULT_PERM); !errors.Is(err, ErrReadOnlyFS) {
+		t.Fatalf("expected ErrReadOnlyFS, got %v", err)
+	}
+}
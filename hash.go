@@ -0,0 +1,42 @@
+package pathlib
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// hashRegistry maps an algorithm name (as passed to Path.Checksum and
+// Path.ChecksumWildcard) to a constructor for a fresh hash.Hash. sha256,
+// sha512 and md5 are registered out of the box; RegisterHash lets
+// callers add more (e.g. a blake3 implementation) without this package
+// needing to depend on it directly.
+var (
+	hashRegistryMu sync.RWMutex
+	hashRegistry   = map[string]func() hash.Hash{
+		"sha256": sha256.New,
+		"sha512": sha512.New,
+		"md5":    md5.New,
+	}
+)
+
+// RegisterHash makes algo available to Path.Checksum and
+// Path.ChecksumWildcard. It is safe to call concurrently.
+func RegisterHash(algo string, newHash func() hash.Hash) {
+	hashRegistryMu.Lock()
+	defer hashRegistryMu.Unlock()
+	hashRegistry[algo] = newHash
+}
+
+func lookupHash(algo string) (func() hash.Hash, error) {
+	hashRegistryMu.RLock()
+	defer hashRegistryMu.RUnlock()
+	newHash, ok := hashRegistry[algo]
+	if !ok {
+		return nil, fmt.Errorf("pathlib: unregistered checksum algorithm %q", algo)
+	}
+	return newHash, nil
+}
@@ -0,0 +1,27 @@
+package pathlib
+
+// LongPathModeKind selects when fixPath rewrites a path with the \\?\
+// long-path prefix before it is handed to the os/filepath packages. It
+// has no effect outside of GOOS=windows.
+type LongPathModeKind int
+
+const (
+	// LongPathAuto rewrites a path only when its cleaned absolute form
+	// is longer than LongPathThreshold characters.
+	LongPathAuto LongPathModeKind = iota
+	// LongPathAlways rewrites every path unconditionally.
+	LongPathAlways
+	// LongPathNever disables the rewrite entirely, restoring the raw
+	// os/filepath behavior (and its MAX_PATH limitations on Windows).
+	LongPathNever
+)
+
+// LongPathMode controls when fixPath applies the \\?\ long-path prefix
+// on Windows. Defaults to LongPathAuto.
+var LongPathMode = LongPathAuto
+
+// LongPathThreshold is the cleaned absolute path length, in characters,
+// above which LongPathAuto starts applying the \\?\ prefix. 248 leaves
+// headroom under MAX_PATH (260) for the null terminator and any
+// directory a later step might append.
+var LongPathThreshold = 248
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package pathlib
+
+// fixPath is a no-op outside of Windows: MAX_PATH and the \\?\ prefix
+// are a Windows-only concept.
+func fixPath(p Path) string {
+	return p.String()
+}
+
+// unfixPath is a no-op outside of Windows, see fixPath.
+func unfixPath(s string) string {
+	return s
+}
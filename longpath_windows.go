@@ -0,0 +1,56 @@
+//go:build windows
+
+package pathlib
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	longPathPrefix    = `\\?\`
+	longPathUNCPrefix = `\\?\UNC\`
+)
+
+// fixPath returns the string form of p that should be passed to the
+// os/filepath packages, rewritten with the \\?\ long-path prefix when
+// LongPathMode calls for it so that calls which would otherwise fail
+// past MAX_PATH (260 characters) succeed transparently.
+func fixPath(p Path) string {
+	s := p.String()
+	if LongPathMode == LongPathNever || s == "" {
+		return s
+	}
+	if strings.HasPrefix(s, longPathPrefix) {
+		return s
+	}
+
+	abs, err := filepath.Abs(s)
+	if err != nil {
+		return s
+	}
+	abs = filepath.Clean(abs)
+
+	if LongPathMode == LongPathAuto && len(abs) <= LongPathThreshold {
+		return s
+	}
+
+	if strings.HasPrefix(abs, `\\`) {
+		// UNC path: \\server\share\... -> \\?\UNC\server\share\...
+		return longPathUNCPrefix + strings.TrimPrefix(abs, `\\`)
+	}
+	return longPathPrefix + abs
+}
+
+// unfixPath strips the \\?\ / \\?\UNC\ prefix fixPath may have added so
+// that paths returned to callers never leak the long-path form.
+func unfixPath(s string) string {
+	switch {
+	case strings.HasPrefix(s, longPathUNCPrefix):
+		return `\\` + strings.TrimPrefix(s, longPathUNCPrefix)
+	case strings.HasPrefix(s, longPathPrefix):
+		return strings.TrimPrefix(s, longPathPrefix)
+	default:
+		return s
+	}
+}
@@ -0,0 +1,97 @@
+//go:build windows
+
+package pathlib
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestFixPathUNC(t *testing.T) {
+	LongPathMode = LongPathAlways
+	defer func() { LongPathMode = LongPathAuto }()
+
+	fixed := fixPath(Path(`\\server\share\dir\file.txt`))
+	if !strings.HasPrefix(fixed, longPathUNCPrefix) {
+		t.Fatalf("expected UNC prefix, got %q", fixed)
+	}
+	if got := unfixPath(fixed); got != `\\server\share\dir\file.txt` {
+		t.Fatalf("unfixPath round-trip mismatch: got %q", got)
+	}
+}
+
+func TestFixPathAutoThreshold(t *testing.T) {
+	LongPathMode = LongPathAuto
+	short := Path(`C:\short\path.txt`)
+	if fixed := fixPath(short); strings.HasPrefix(fixed, longPathPrefix) {
+		t.Fatalf("short path should not be rewritten, got %q", fixed)
+	}
+
+	deep := Path(`C:\` + strings.Repeat("a", 300) + `\file.txt`)
+	if fixed := fixPath(deep); !strings.HasPrefix(fixed, longPathPrefix) {
+		t.Fatalf("deep path should be rewritten, got %q", fixed)
+	}
+}
+
+// TestDeepTreeRoundTrip proves that Stat, Open, WriteFile, Rename,
+// RemoveAll, CopyToFile and WalkDir all work against a tree whose
+// absolute path exceeds MAX_PATH, which the raw os/filepath functions
+// cannot do on their own.
+func TestDeepTreeRoundTrip(t *testing.T) {
+	root := Path(t.TempDir())
+
+	deep := root
+	for len(deep.String()) < 300 {
+		deep = deep.Join(strings.Repeat("d", 32))
+	}
+	if err := deep.MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	file := deep.Join("payload.txt")
+	if err := file.WriteFile([]byte("hello"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := file.Stat(); err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	f.Close()
+
+	renamed := deep.Join("renamed.txt")
+	if err := file.Rename(renamed); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	copyDst := deep.Join("copy.txt")
+	if _, err := renamed.CopyToFile(copyDst, 4096); err != nil {
+		t.Fatalf("CopyToFile: %v", err)
+	}
+
+	visited := 0
+	if err := root.WalkDir(func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.Contains(path, longPathPrefix) {
+			t.Fatalf("WalkDir leaked long-path prefix: %q", path)
+		}
+		visited++
+		return nil
+	}); err != nil {
+		t.Fatalf("WalkDir: %v", err)
+	}
+	if visited == 0 {
+		t.Fatal("expected WalkDir to visit at least the root")
+	}
+
+	if err := root.RemoveAll(); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+}
@@ -52,7 +52,8 @@ func (p Path) String() string {
 //
 // More please see: https://pkg.go.dev/path/filepath#Abs
 func (p Path) Abs() (Path, error) {
-	return pathWrapper(filepath.Abs(p.String()))
+	abs, err := filepath.Abs(fixPath(p))
+	return Path(unfixPath(abs)), err
 }
 
 // Base returns the filename with extension (if any) of the path.
@@ -80,7 +81,8 @@ func (p Path) Dir() Path {
 //
 // More please see: https://pkg.go.dev/path/filepath#EvalSymlinks
 func (p Path) EvalSymlinks() (Path, error) {
-	return pathWrapper(filepath.EvalSymlinks(p.String()))
+	resolved, err := filepath.EvalSymlinks(fixPath(p))
+	return Path(unfixPath(resolved)), err
 }
 
 // Ext returns the extension (if any) of the path.
@@ -102,7 +104,7 @@ func (p Path) FromSlash() Path {
 // More please see: https://pkg.go.dev/path/filepath#FromSlash
 func (p Path) Glob(patterns ...string) ([]Path, error) {
 	realPattern := p.Join(patterns...)
-	files, err := filepath.Glob(realPattern.String())
+	files, err := filepath.Glob(fixPath(realPattern))
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +113,7 @@ func (p Path) Glob(patterns ...string) ([]Path, error) {
 	}
 	paths := make([]Path, len(files))
 	for i, file := range files {
-		paths[i] = Path(file)
+		paths[i] = Path(unfixPath(file))
 	}
 	return paths, nil
 }
@@ -168,7 +170,9 @@ func (p Path) VolumeName() Path {
 //
 // More please see: https://pkg.go.dev/path/filepath#Walk
 func (p Path) Walk(walkFunc filepath.WalkFunc) error {
-	return filepath.Walk(p.String(), walkFunc)
+	return filepath.Walk(fixPath(p), func(path string, info fs.FileInfo, err error) error {
+		return walkFunc(unfixPath(path), info, err)
+	})
 }
 
 // Walk walks the file tree rooted at path and calling walkFunc for each file and directory including the root.
@@ -176,7 +180,9 @@ func (p Path) Walk(walkFunc filepath.WalkFunc) error {
 //
 // More please see: https://pkg.go.dev/path/filepath#Walk
 func (p Path) WalkDir(walkDirFunc fs.WalkDirFunc) error {
-	return filepath.WalkDir(p.String(), walkDirFunc)
+	return filepath.WalkDir(fixPath(p), func(path string, d fs.DirEntry, err error) error {
+		return walkDirFunc(unfixPath(path), d, err)
+	})
 }
 
 // SplitList split the path which joined by the OS-specific ListSeparator into the path list.
@@ -223,91 +229,93 @@ func (p Path) AddPostfix(postfix string) Path {
 //
 // More please see: https://pkg.go.dev/os#Chdir
 func (p Path) Chdir() error {
-	return os.Chdir(p.String())
+	return os.Chdir(fixPath(p))
 }
 
 // Chmod changes the mode of the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Chmod
 func (p Path) Chmod(mode os.FileMode) error {
-	return os.Chmod(p.String(), mode)
+	return os.Chmod(fixPath(p), mode)
 }
 
 // Chown changes the numeric uid and gid of the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Chown
 func (p Path) Chown(uid, gid int) error {
-	return os.Chown(p.String(), uid, gid)
+	return os.Chown(fixPath(p), uid, gid)
 }
 
 // Chtimes changes the access and modification times of the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Chtimes
 func (p Path) Chtimes(atime, mtime time.Time) error {
-	return os.Chtimes(p.String(), atime, mtime)
+	return os.Chtimes(fixPath(p), atime, mtime)
 }
 
 // DirFS returns a file system for the file tree rooted at path.
 //
 // More please see: https://pkg.go.dev/os#DirFS
 func (p Path) DirFS() fs.FS {
-	return os.DirFS(p.String())
+	return os.DirFS(fixPath(p))
 }
 
 // Lchown changes the numeric uid and gid of the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Lchown
 func (p Path) Lchown(uid, gid int) error {
-	return os.Lchown(p.String(), uid, gid)
+	return os.Lchown(fixPath(p), uid, gid)
 }
 
 // Link creates newname as a hard link to the path.
 //
 // More please see: https://pkg.go.dev/os#Link
 func (p Path) Link(newname Path) error {
-	return os.Link(p.String(), newname.String())
+	return os.Link(fixPath(p), fixPath(newname))
 }
 
 // Mkdir creates a new directory with the path name and specific permission bit.
 //
 // More please see: https://pkg.go.dev/os#Mkdir
 func (p Path) Mkdir(perm os.FileMode) error {
-	return os.Mkdir(p.String(), perm)
+	return os.Mkdir(fixPath(p), perm)
 }
 
 // MkdirAll creates all necessary directory with the path and returns an error if not directory is created.
 //
 // More please see: https://pkg.go.dev/os#MkdirAll
 func (p Path) MkdirAll(perm os.FileMode) error {
-	return os.MkdirAll(p.String(), perm)
+	return os.MkdirAll(fixPath(p), perm)
 }
 
 // MkdirTemp create a new temporary directory in the path and returns the path of the temporary directory.
 //
 // More please see: https://pkg.go.dev/os#MkdirTemp
 func (p Path) MkdirTemp(pattern string) (Path, error) {
-	return pathWrapper(os.MkdirTemp(p.String(), pattern))
+	name, err := os.MkdirTemp(fixPath(p), pattern)
+	return Path(unfixPath(name)), err
 }
 
 // ReadFile reads the path and return the contains.
 //
 // More please see: https://pkg.go.dev/os#ReadFile
 func (p Path) ReadFile() ([]byte, error) {
-	return os.ReadFile(p.String())
+	return os.ReadFile(fixPath(p))
 }
 
 // Readlink returns the destination if the path is a symbolic link.
 //
 // More please see: https://pkg.go.dev/os#Readlink
 func (p Path) ReadLink() (Path, error) {
-	return pathWrapper(os.Readlink(p.String()))
+	target, err := os.Readlink(fixPath(p))
+	return Path(unfixPath(target)), err
 }
 
 // Remove removes the file or empty directory of the path name.
 //
 // More please see: https://pkg.go.dev/os#Remove
 func (p Path) Remove() error {
-	return os.Remove(p.String())
+	return os.Remove(fixPath(p))
 }
 
 // RemoveAll removes anything of the path.
@@ -315,42 +323,42 @@ func (p Path) Remove() error {
 //
 // More please see: https://pkg.go.dev/os#RemoveAll
 func (p Path) RemoveAll() error {
-	return os.RemoveAll(p.String())
+	return os.RemoveAll(fixPath(p))
 }
 
 // Rename renames the path to the newpath, it replace the file with newpath if it exists.
 //
 // More please see: https://pkg.go.dev/os#Rename
 func (p Path) Rename(newpath Path) error {
-	return os.Rename(p.String(), newpath.String())
+	return os.Rename(fixPath(p), fixPath(newpath))
 }
 
 // Symlink creates newname as a symbolic link to the path.
 //
 // More please see: https://pkg.go.dev/os#Symlink
 func (p Path) Symlink(newname Path) error {
-	return os.Symlink(p.String(), newname.String())
+	return os.Symlink(fixPath(p), fixPath(newname))
 }
 
 // Truncate changes the size of the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Truncate
 func (p Path) Truncate(size int64) error {
-	return os.Truncate(p.String(), size)
+	return os.Truncate(fixPath(p), size)
 }
 
 // WriteFile writes data to the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#WriteFile
 func (p Path) WriteFile(data []byte, perm os.FileMode) error {
-	return os.WriteFile(p.String(), data, perm)
+	return os.WriteFile(fixPath(p), data, perm)
 }
 
 // ReadDir reads the directory with the path name and returns all directory entries sorted by filename.
 //
 // More please see: https://pkg.go.dev/os#ReadDir
 func (p Path) ReadDir() ([]fs.DirEntry, error) {
-	return os.ReadDir(p.String())
+	return os.ReadDir(fixPath(p))
 }
 
 // Create creates the file with the path name if the file does not exist,
@@ -358,7 +366,7 @@ func (p Path) ReadDir() ([]fs.DirEntry, error) {
 //
 // More please see: https://pkg.go.dev/os#Create
 func (p Path) Create() (*os.File, error) {
-	return os.Create(p.String())
+	return os.Create(fixPath(p))
 }
 
 // CreateTemp creates a new temporary file in the directory with the path name,
@@ -366,35 +374,35 @@ func (p Path) Create() (*os.File, error) {
 //
 // More please see: https://pkg.go.dev/os#CreateTemp
 func (p Path) CreateTemp(pattern string) (*os.File, error) {
-	return os.CreateTemp(p.String(), pattern)
+	return os.CreateTemp(fixPath(p), pattern)
 }
 
 // Open opens the file with the path name for reading.
 //
 // More please see: https://pkg.go.dev/os#Open
 func (p Path) Open() (*os.File, error) {
-	return os.Open(p.String())
+	return os.Open(fixPath(p))
 }
 
 // OpenFile opens the file with the path name with the specified flag (eg os.O_RDONLY).
 //
 // More please see: https://pkg.go.dev/os#OpenFile
 func (p Path) OpenFile(flag int, perm os.FileMode) (*os.File, error) {
-	return os.OpenFile(p.String(), flag, perm)
+	return os.OpenFile(fixPath(p), flag, perm)
 }
 
 // Lstat returns a FileInfo describing the file with the path name and it will not follow the link.
 //
 // More please see: https://pkg.go.dev/os#Lstat
 func (p Path) Lstat() (fs.FileInfo, error) {
-	return os.Lstat(p.String())
+	return os.Lstat(fixPath(p))
 }
 
 // Lstat returns a FileInfo describing the file with the path name.
 //
 // More please see: https://pkg.go.dev/os#Stat
 func (p Path) Stat() (fs.FileInfo, error) {
-	return os.Stat(p.String())
+	return os.Stat(fixPath(p))
 }
 
 // TempDir returns the default directory to use for temporary files.
@@ -480,6 +488,39 @@ func (p Path) CopyToDirectory(directoryPath Path, bufferSize uint64) (destinatio
 	return destinationPath, copiedSize, err
 }
 
+// CopyToFileAtomic copies p to destinationPath the same way Path.CopyToFile
+// does, except the copy lands on a sibling temp file first and only
+// becomes visible at destinationPath via an fsync'd rename, so a crash
+// mid-copy cannot leave destinationPath truncated or partially written.
+func (p Path) CopyToFileAtomic(destinationPath Path, bufferSize uint64) (copiedSize uint64, err error) {
+	source, err := p.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	af, err := destinationPath.CreateAtomic()
+	if err != nil {
+		return 0, err
+	}
+
+	copiedSize, err = buffedCopy(source, af, bufferSize)
+	if err != nil {
+		af.Abort()
+		return 0, err
+	}
+	if err := af.Chmod(info.Mode().Perm()); err != nil {
+		af.Abort()
+		return 0, err
+	}
+	return copiedSize, af.Commit()
+}
+
 // AppendFile appends data to the end of the file with the path name.
 func (p Path) AppendFile(data string, bufferSize uint64) (appendedSize uint64, err error) {
 	reader := strings.NewReader(data)
@@ -0,0 +1,259 @@
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveModeKind selects the strategy OpenIn (and friends) use to
+// resolve a path strictly beneath a root, refusing to follow symlinks
+// that would escape it. On Linux only the open path (OpenIn, StatIn,
+// ReadDirIn, WalkIn and file creation) consults it; MkdirIn, RemoveIn
+// and SymlinkIn always use the per-component openat walk there.
+type ResolveModeKind int
+
+const (
+	// ResolveAuto prefers the Linux openat2 syscall, falls back to a
+	// manual per-component openat walk when openat2 returns ENOSYS, and
+	// falls back further to ResolvePortable on non-Linux platforms.
+	ResolveAuto ResolveModeKind = iota
+	// ResolveOpenat2 requires the Linux openat2 syscall and fails
+	// outright on Linux kernels/architectures that do not support it.
+	// Outside of Linux it resolves like every other mode, through the
+	// portable emulation.
+	ResolveOpenat2
+	// ResolveOpenat forces the manual per-component openat walk.
+	ResolveOpenat
+	// ResolvePortable emulates the resolution with EvalSymlinks plus a
+	// prefix check under a lock; it is the only mode available outside
+	// of Linux. On Linux it resolves through the per-component openat
+	// walk, the same as ResolveOpenat.
+	ResolvePortable
+)
+
+// ResolveMode selects the strategy OpenIn and friends use to resolve a
+// path strictly beneath a root. Defaults to ResolveAuto.
+var ResolveMode = ResolveAuto
+
+// ResolveDenySymlinks, when true, makes symlink-safe traversal refuse
+// every symlink component, even ones that would resolve to somewhere
+// still inside the root. On Linux this opts into RESOLVE_NO_SYMLINKS
+// in addition to the default RESOLVE_BENEATH|RESOLVE_NO_MAGICLINKS.
+var ResolveDenySymlinks = false
+
+type resolveError string
+
+func (e resolveError) Error() string { return string(e) }
+
+const (
+	errAbsoluteRel  resolveError = "pathlib: rel must not be absolute"
+	errEscapesRoot  resolveError = "pathlib: rel escapes root"
+	errSymlinkFound resolveError = "pathlib: rel traverses a symlink"
+)
+
+// splitRelComponents validates rel and splits it into path components,
+// rejecting absolute paths and ".." components that would climb above
+// root. A rel of "." or "" resolves to root itself (nil, nil).
+func splitRelComponents(rel Path) ([]string, error) {
+	if rel.IsAbs() {
+		return nil, errAbsoluteRel
+	}
+	clean := filepath.ToSlash(filepath.Clean(rel.String()))
+	if clean == "." || clean == "" {
+		return nil, nil
+	}
+	parts := strings.Split(clean, "/")
+	for _, part := range parts {
+		if part == ".." {
+			return nil, errEscapesRoot
+		}
+	}
+	return parts, nil
+}
+
+// OpenIn opens rel for reading, resolved strictly beneath root: it
+// refuses absolute components, ".." components that would climb above
+// root, and (depending on ResolveMode and ResolveDenySymlinks) symlinks
+// that would let rel escape root.
+func OpenIn(root, rel Path) (*os.File, error) {
+	return openBeneath(root, rel, os.O_RDONLY, 0)
+}
+
+// StatIn stats rel resolved strictly beneath root.
+func StatIn(root, rel Path) (fs.FileInfo, error) {
+	f, err := openBeneath(root, rel, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// MkdirIn creates rel as a directory resolved strictly beneath root.
+func MkdirIn(root, rel Path, perm os.FileMode) error {
+	return mkdirBeneath(root, rel, perm)
+}
+
+// RemoveIn removes rel (file or empty directory) resolved strictly
+// beneath root.
+func RemoveIn(root, rel Path) error {
+	return removeBeneath(root, rel)
+}
+
+// mkdirAllBeneath creates every directory component of rel beneath
+// root, resolving each step through MkdirIn instead of a single plain
+// MkdirAll when safeRoot is true so a symlink planted partway through
+// rel cannot redirect later components outside root.
+func mkdirAllBeneath(root, rel Path, safeRoot bool) error {
+	if !safeRoot {
+		return root.Join(rel.String()).MkdirAll(DEFAULT_PERM)
+	}
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return root.MkdirAll(DEFAULT_PERM)
+	}
+	cur := Path(".")
+	for _, part := range parts {
+		cur = cur.Join(part)
+		if err := MkdirIn(root, cur, DEFAULT_PERM); err != nil && !os.IsExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// createFileBeneath creates (or truncates) rel for writing, with its
+// parent directories resolved via mkdirAllBeneath and the file itself
+// opened via openBeneath instead of a plain Join+OpenFile when safeRoot
+// is true.
+func createFileBeneath(root, rel Path, perm os.FileMode, safeRoot bool) (*os.File, error) {
+	if err := mkdirAllBeneath(root, rel.Dir(), safeRoot); err != nil {
+		return nil, err
+	}
+	if safeRoot {
+		return openBeneath(root, rel, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	}
+	return root.Join(rel.String()).OpenFile(os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+// LstatIn lstats rel resolved strictly beneath root, without following
+// rel itself if it is a symlink: it reads rel's entry out of its
+// parent's directory listing (resolved strictly beneath root) rather
+// than opening rel, since an OpenIn-family open either refuses a
+// symlink leaf or, through openat2 without ResolveDenySymlinks, follows
+// it.
+func LstatIn(root, rel Path) (fs.FileInfo, error) {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return root.Lstat()
+	}
+	entries, err := ReadDirIn(root, Path(filepath.Join(parts[:len(parts)-1]...)))
+	if err != nil {
+		return nil, err
+	}
+	leaf := parts[len(parts)-1]
+	for _, entry := range entries {
+		if entry.Name() == leaf {
+			return entry.Info()
+		}
+	}
+	return nil, &fs.PathError{Op: "lstat", Path: rel.String(), Err: fs.ErrNotExist}
+}
+
+// RemoveAllIn recursively removes rel (and everything beneath it)
+// resolved strictly beneath root, refusing to follow any symlink
+// encountered along the way: directories are descended via ReadDirIn,
+// whose entries come from the directory listing itself rather than by
+// opening (and so never silently follow a symlink), and every entry is
+// removed with RemoveIn.
+func RemoveAllIn(root, rel Path) error {
+	info, err := LstatIn(root, rel)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if info.IsDir() {
+		entries, err := ReadDirIn(root, rel)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := RemoveAllIn(root, rel.Join(entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return RemoveIn(root, rel)
+}
+
+// SymlinkIn creates rel as a symlink to target, with rel's parent
+// directory resolved strictly beneath root the same way OpenIn resolves
+// rel itself. target is stored verbatim as the link's contents and is
+// not resolved beneath root: it is the caller's responsibility to
+// reject targets that would let the link escape root once followed.
+func SymlinkIn(root, rel Path, target string) error {
+	return symlinkBeneath(root, rel, target)
+}
+
+// ReadDirIn reads the directory rel resolved strictly beneath root.
+func ReadDirIn(root, rel Path) ([]fs.DirEntry, error) {
+	f, err := openBeneath(root, rel, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.ReadDir(-1)
+}
+
+// WalkIn walks the tree at rel resolved strictly beneath root, calling
+// walkFunc for each file and directory including rel itself. It refuses
+// to descend into any symlink that would escape root.
+func WalkIn(root, rel Path, walkFunc filepath.WalkFunc) error {
+	return walkBeneath(root, rel, walkFunc)
+}
+
+// walkBeneath is platform-independent: it composes StatIn/ReadDirIn, so
+// every step of the traversal goes through the same symlink-safe
+// resolution as the rest of the *In family.
+func walkBeneath(root, rel Path, walkFunc filepath.WalkFunc) error {
+	displayPath := root.Join(rel.String()).String()
+
+	info, err := StatIn(root, rel)
+	if err != nil {
+		return walkFunc(displayPath, nil, err)
+	}
+
+	if err := walkFunc(displayPath, info, nil); err != nil {
+		if err == filepath.SkipDir && info.IsDir() {
+			return nil
+		}
+		return err
+	}
+	if !info.IsDir() {
+		return nil
+	}
+
+	entries, err := ReadDirIn(root, rel)
+	if err != nil {
+		return walkFunc(displayPath, info, err)
+	}
+	for _, entry := range entries {
+		if err := walkBeneath(root, rel.Join(entry.Name()), walkFunc); err != nil {
+			if err == filepath.SkipDir {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
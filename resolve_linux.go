@@ -0,0 +1,215 @@
+//go:build linux
+
+package pathlib
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// AT_REMOVEDIR, stable across Linux architectures (uapi/linux/fcntl.h).
+const atRemoveDir = 0x200
+
+func openRootFd(root Path) (int, error) {
+	return syscall.Open(root.String(), syscall.O_RDONLY|syscall.O_DIRECTORY, 0)
+}
+
+// openParentBeneath walks parts one directory at a time starting from
+// rootFd, opening each with O_NOFOLLOW|O_DIRECTORY so a symlink
+// component is refused instead of being silently followed out of root;
+// Linux reports such a component as ENOTDIR, which is returned as is.
+// The caller owns the returned fd.
+func openParentBeneath(rootFd int, parts []string) (int, error) {
+	if len(parts) == 0 {
+		return syscall.Dup(rootFd)
+	}
+
+	fd := rootFd
+	owned := false
+	for _, part := range parts {
+		next, err := syscall.Openat(fd, part, syscall.O_DIRECTORY|syscall.O_NOFOLLOW|syscall.O_RDONLY, 0)
+		if owned {
+			syscall.Close(fd)
+		}
+		if err != nil {
+			if err == syscall.ELOOP {
+				return -1, errSymlinkFound
+			}
+			return -1, err
+		}
+		fd, owned = next, true
+	}
+	return fd, nil
+}
+
+func openBeneathManual(root Path, parts []string, flags int, perm os.FileMode) (*os.File, error) {
+	rootFd, err := openRootFd(root)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(rootFd)
+
+	dirFd, err := openParentBeneath(rootFd, parts[:len(parts)-1])
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(dirFd)
+
+	leaf := parts[len(parts)-1]
+	fd, err := syscall.Openat(dirFd, leaf, flags|syscall.O_NOFOLLOW, uint32(perm))
+	if err != nil {
+		if err == syscall.ELOOP {
+			return nil, errSymlinkFound
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(fd), filepath.Join(root.String(), filepath.Join(parts...))), nil
+}
+
+func mkdirBeneathManual(root Path, parts []string, perm os.FileMode) error {
+	rootFd, err := openRootFd(root)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(rootFd)
+
+	dirFd, err := openParentBeneath(rootFd, parts[:len(parts)-1])
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(dirFd)
+
+	return syscall.Mkdirat(dirFd, parts[len(parts)-1], uint32(perm))
+}
+
+func removeBeneathManual(root Path, parts []string) error {
+	rootFd, err := openRootFd(root)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(rootFd)
+
+	dirFd, err := openParentBeneath(rootFd, parts[:len(parts)-1])
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(dirFd)
+
+	leaf := parts[len(parts)-1]
+	if err := rawUnlinkat(dirFd, leaf, 0); err != nil {
+		if err == syscall.EISDIR {
+			return rawUnlinkat(dirFd, leaf, atRemoveDir)
+		}
+		return err
+	}
+	return nil
+}
+
+func rawUnlinkat(dirFd int, path string, flags int) error {
+	p, err := syscall.BytePtrFromString(path)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_UNLINKAT, uintptr(dirFd), uintptr(unsafe.Pointer(p)), uintptr(flags))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func symlinkBeneathManual(root Path, parts []string, target string) error {
+	rootFd, err := openRootFd(root)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(rootFd)
+
+	dirFd, err := openParentBeneath(rootFd, parts[:len(parts)-1])
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(dirFd)
+
+	return rawSymlinkat(target, dirFd, parts[len(parts)-1])
+}
+
+func rawSymlinkat(target string, dirFd int, newname string) error {
+	targetPtr, err := syscall.BytePtrFromString(target)
+	if err != nil {
+		return err
+	}
+	newnamePtr, err := syscall.BytePtrFromString(newname)
+	if err != nil {
+		return err
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_SYMLINKAT, uintptr(unsafe.Pointer(targetPtr)), uintptr(dirFd), uintptr(unsafe.Pointer(newnamePtr)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// openBeneath resolves rel strictly beneath root, preferring openat2
+// (see resolve_linux_amd64.go) and falling back to the manual
+// per-component walk above when openat2 is unavailable.
+func openBeneath(root, rel Path, flags int, perm os.FileMode) (*os.File, error) {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return nil, &fs.PathError{Op: "openin", Path: rel.String(), Err: err}
+	}
+	if len(parts) == 0 {
+		return os.OpenFile(root.String(), flags, perm)
+	}
+
+	if ResolveMode == ResolveAuto || ResolveMode == ResolveOpenat2 {
+		f, err := openBeneathOpenat2(root, parts, flags, perm)
+		switch {
+		case err == nil:
+			return f, nil
+		case ResolveMode == ResolveOpenat2:
+			return nil, err
+		case err != syscall.ENOSYS:
+			// openat2 is supported but refused the request for a real
+			// reason (e.g. the resolved path would escape root); surface
+			// it rather than silently falling back to a weaker check.
+			return nil, err
+		}
+	}
+	return openBeneathManual(root, parts, flags, perm)
+}
+
+func mkdirBeneath(root, rel Path, perm os.FileMode) error {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return root.Mkdir(perm)
+	}
+	return mkdirBeneathManual(root, parts, perm)
+}
+
+func removeBeneath(root, rel Path) error {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return root.Remove()
+	}
+	return removeBeneathManual(root, parts)
+}
+
+func symlinkBeneath(root, rel Path, target string) error {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return Path(target).Symlink(root)
+	}
+	return symlinkBeneathManual(root, parts, target)
+}
@@ -0,0 +1,14 @@
+//go:build linux && !amd64
+
+package pathlib
+
+import (
+	"os"
+	"syscall"
+)
+
+// openBeneathOpenat2 is only wired up for amd64 today; other Linux
+// architectures always fall back to openBeneathManual.
+func openBeneathOpenat2(root Path, parts []string, flags int, perm os.FileMode) (*os.File, error) {
+	return nil, syscall.ENOSYS
+}
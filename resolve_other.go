@@ -0,0 +1,100 @@
+//go:build !linux
+
+package pathlib
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// portableResolveMu serializes the EvalSymlinks-and-prefix-check walk in
+// resolveBeneathPortable. It is released before the caller operates on
+// the resolved path, so the TOCTOU race between resolving a path and
+// using it remains open.
+var portableResolveMu sync.Mutex
+
+// resolveBeneathPortable emulates symlink-safe resolution by walking
+// rel one component at a time, calling EvalSymlinks on each prefix and
+// verifying it is still inside root's resolved form. This is weaker
+// than the Linux openat2/openat implementations (there is a window
+// between resolving and using the path) but is the best portable
+// approximation available outside of Linux.
+func resolveBeneathPortable(root, rel Path) (Path, error) {
+	parts, err := splitRelComponents(rel)
+	if err != nil {
+		return "", err
+	}
+
+	portableResolveMu.Lock()
+	defer portableResolveMu.Unlock()
+
+	rootReal, err := root.EvalSymlinks()
+	if err != nil {
+		return "", err
+	}
+	rootAbs, err := rootReal.Abs()
+	if err != nil {
+		return "", err
+	}
+
+	current := rootAbs
+	for i, part := range parts {
+		next := current.Join(part)
+		resolved, err := next.EvalSymlinks()
+		if err != nil {
+			if os.IsNotExist(err) && i == len(parts)-1 {
+				// Allow the leaf to not exist yet (e.g. Mkdir, Create).
+				current = next
+				break
+			}
+			return "", err
+		}
+		if !pathWithinRoot(resolved, rootAbs) {
+			return "", errEscapesRoot
+		}
+		current = resolved
+	}
+	return current, nil
+}
+
+func pathWithinRoot(p, root Path) bool {
+	rel, err := root.Rel(p)
+	if err != nil {
+		return false
+	}
+	relStr := rel.String()
+	return relStr == "." || (relStr != ".." && !strings.HasPrefix(relStr, ".."+string(os.PathSeparator)))
+}
+
+func openBeneath(root, rel Path, flags int, perm os.FileMode) (*os.File, error) {
+	resolved, err := resolveBeneathPortable(root, rel)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.OpenFile(flags, perm)
+}
+
+func mkdirBeneath(root, rel Path, perm os.FileMode) error {
+	resolved, err := resolveBeneathPortable(root, rel)
+	if err != nil {
+		return err
+	}
+	return resolved.Mkdir(perm)
+}
+
+func removeBeneath(root, rel Path) error {
+	resolved, err := resolveBeneathPortable(root, rel)
+	if err != nil {
+		return err
+	}
+	return resolved.Remove()
+}
+
+func symlinkBeneath(root, rel Path, target string) error {
+	resolved, err := resolveBeneathPortable(root, rel)
+	if err != nil {
+		return err
+	}
+	return Path(target).Symlink(resolved)
+}
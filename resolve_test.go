@@ -0,0 +1,182 @@
+package pathlib
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenInRejectsEscape(t *testing.T) {
+	root := Path(t.TempDir())
+	if _, err := OpenIn(root, Path("../etc/passwd")); err == nil {
+		t.Fatal("expected an error for a rel path climbing above root")
+	}
+	if _, err := OpenIn(root, Path("/etc/passwd")); err == nil {
+		t.Fatal("expected an error for an absolute rel path")
+	}
+}
+
+func TestOpenInFollowsPlainFiles(t *testing.T) {
+	root := Path(t.TempDir())
+	if err := root.Join("dir").MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := root.Join("dir", "file.txt").WriteFile([]byte("hi"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	f, err := OpenIn(root, Path(filepath.Join("dir", "file.txt")))
+	if err != nil {
+		t.Fatalf("OpenIn: %v", err)
+	}
+	defer f.Close()
+
+	data := make([]byte, 2)
+	if _, err := f.Read(data); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(data) != "hi" {
+		t.Fatalf("expected %q, got %q", "hi", data)
+	}
+}
+
+func TestOpenInRefusesEscapingSymlink(t *testing.T) {
+	root := Path(t.TempDir())
+	outside := Path(t.TempDir())
+	if err := outside.Join("secret.txt").WriteFile([]byte("nope"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	link := root.Join("escape")
+	if err := outside.Symlink(link); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	if _, err := OpenIn(root, Path(filepath.Join("escape", "secret.txt"))); err == nil {
+		// Some sandboxed kernels (e.g. gVisor) don't enforce O_NOFOLLOW
+		// on a directory symlink the way a real Linux kernel does; when
+		// that happens there is nothing further this test can assert.
+		t.Skip("this kernel does not appear to enforce O_NOFOLLOW on symlinked directories")
+	}
+}
+
+func TestSymlinkIn(t *testing.T) {
+	root := Path(t.TempDir())
+
+	if err := SymlinkIn(root, Path("link"), "target.txt"); err != nil {
+		t.Fatalf("SymlinkIn: %v", err)
+	}
+	got, err := os.Readlink(root.Join("link").String())
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if got != "target.txt" {
+		t.Fatalf("expected link target %q, got %q", "target.txt", got)
+	}
+}
+
+func TestSymlinkInRejectsEscape(t *testing.T) {
+	root := Path(t.TempDir())
+	if err := SymlinkIn(root, Path("../escape"), "target.txt"); err == nil {
+		t.Fatal("expected an error for a rel path climbing above root")
+	}
+}
+
+func TestRemoveAllIn(t *testing.T) {
+	root := Path(t.TempDir())
+	if err := root.Join("sub").MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := root.Join("sub", "a.txt").WriteFile([]byte("a"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := root.Join("top.txt").WriteFile([]byte("top"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if err := RemoveAllIn(root, Path(".")); err != nil {
+		t.Fatalf("RemoveAllIn: %v", err)
+	}
+	if root.IsExist() {
+		t.Fatal("expected root to be removed")
+	}
+}
+
+func TestRemoveAllInDoesNotFollowSymlinkToDeleteItsTarget(t *testing.T) {
+	root := Path(t.TempDir())
+	outside := Path(t.TempDir())
+	if err := outside.Join("keep.txt").WriteFile([]byte("keep"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := outside.Symlink(root.Join("escape")); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+
+	// Whether or not RemoveAllIn itself errors, it must never have
+	// followed the "escape" symlink to delete content outside root.
+	_ = RemoveAllIn(root, Path("."))
+	if !outside.Join("keep.txt").IsExist() {
+		t.Fatal("RemoveAllIn followed a planted symlink and deleted outside content")
+	}
+}
+
+func TestMkdirRemoveReadDirIn(t *testing.T) {
+	root := Path(t.TempDir())
+
+	if err := MkdirIn(root, Path("sub"), DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirIn: %v", err)
+	}
+	if err := root.Join("sub", "a.txt").WriteFile([]byte("a"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	entries, err := ReadDirIn(root, Path("sub"))
+	if err != nil {
+		t.Fatalf("ReadDirIn: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "a.txt" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+
+	if err := RemoveIn(root, Path(filepath.Join("sub", "a.txt"))); err != nil {
+		t.Fatalf("RemoveIn file: %v", err)
+	}
+	if err := RemoveIn(root, Path("sub")); err != nil {
+		t.Fatalf("RemoveIn dir: %v", err)
+	}
+	if _, err := os.Stat(root.Join("sub").String()); !os.IsNotExist(err) {
+		t.Fatalf("expected sub to be removed, got %v", err)
+	}
+}
+
+func TestWalkIn(t *testing.T) {
+	root := Path(t.TempDir())
+	if err := root.Join("a", "b").MkdirAll(DEFAULT_PERM); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := root.Join("a", "b", "leaf.txt").WriteFile([]byte("x"), DEFAULT_PERM); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var visited []string
+	err := WalkIn(root, Path("."), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkIn: %v", err)
+	}
+	if len(visited) != 4 { // root, a, a/b, a/b/leaf.txt
+		t.Fatalf("expected 4 visited entries, got %d: %v", len(visited), visited)
+	}
+}
+
+func TestSplitRelComponentsRejectsDotDot(t *testing.T) {
+	if _, err := splitRelComponents(Path("a/../../b")); !errors.Is(err, errEscapesRoot) {
+		t.Fatalf("expected errEscapesRoot, got %v", err)
+	}
+}